@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
+	"go-rails/framework/cli"
 	"go-rails/framework/core"
+	"go-rails/framework/database"
 	"go-rails/framework/generators"
+	"go-rails/framework/inflector"
 
+	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
@@ -34,16 +40,49 @@ var newCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		appName := args[0]
-		if err := generators.CreateNewApp(appName); err != nil {
+		options := fileOptions(cmd)
+		flags := cmd.Flags()
+		var appOptions generators.NewAppOptions
+		appOptions.FrameworkPath, _ = flags.GetString("framework-path")
+		appOptions.SkipTidy, _ = flags.GetBool("skip-tidy")
+		appOptions.Database, _ = flags.GetString("database")
+		appOptions.API, _ = flags.GetBool("api")
+		appOptions.SkipAuth, _ = flags.GetBool("skip-auth")
+		appOptions.Minimal, _ = flags.GetBool("minimal")
+		appOptions.Template, _ = flags.GetString("template")
+		if err := generators.CreateNewApp(appName, appOptions, options); err != nil {
 			log.Fatal(err)
 		}
-		fmt.Printf("Created new Go-Rails application: %s\n", appName)
+		if !options.Pretend {
+			fmt.Printf("Created new Go-Rails application: %s\n", appName)
+		}
 	},
 }
 
 var generateCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "Generate files for your application",
+	Use:               "generate",
+	Short:             "Generate files for your application",
+	PersistentPreRunE: loadInflections,
+}
+
+// addFileFlags добавляет флаги, управляющие записью сгенерированных файлов
+func addFileFlags(flags *pflag.FlagSet) {
+	flags.BoolP("force", "f", false, "overwrite files that already exist with different content")
+	flags.BoolP("skip", "s", false, "keep files that already exist with different content")
+	flags.BoolP("pretend", "p", false, "only print what would be done, without writing files")
+}
+
+// fileOptions читает флаги записи файлов
+func fileOptions(cmd *cobra.Command) generators.Options {
+	force, _ := cmd.Flags().GetBool("force")
+	skip, _ := cmd.Flags().GetBool("skip")
+	pretend, _ := cmd.Flags().GetBool("pretend")
+	return generators.Options{Force: force, Skip: skip, Pretend: pretend}
+}
+
+// loadInflections подключает правила склонения приложения из config/inflections.yml
+func loadInflections(cmd *cobra.Command, args []string) error {
+	return inflector.LoadFile(filepath.Join("config", "inflections.yml"))
 }
 
 var generateControllerCmd = &cobra.Command{
@@ -52,10 +91,13 @@ var generateControllerCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		controllerName := args[0]
-		if err := generators.GenerateController(controllerName); err != nil {
+		options := fileOptions(cmd)
+		if err := generators.GenerateController(controllerName, options); err != nil {
 			log.Fatal(err)
 		}
-		fmt.Printf("Generated controller: %s\n", controllerName)
+		if !options.Pretend {
+			fmt.Printf("Generated controller: %s\n", controllerName)
+		}
 	},
 }
 
@@ -66,10 +108,34 @@ var generateModelCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		modelName := args[0]
 		fields := args[1:]
-		if err := generators.GenerateModel(modelName, fields); err != nil {
+		softDelete, _ := cmd.Flags().GetBool("soft-delete")
+		options := fileOptions(cmd)
+		modelOptions := generators.ModelOptions{SoftDelete: softDelete}
+		if err := generators.GenerateModel(modelName, fields, modelOptions, options); err != nil {
 			log.Fatal(err)
 		}
-		fmt.Printf("Generated model: %s\n", modelName)
+		if !options.Pretend {
+			fmt.Printf("Generated model: %s\n", modelName)
+		}
+	},
+}
+
+var generateScaffoldCmd = &cobra.Command{
+	Use:   "scaffold [name] [fields...]",
+	Short: "Generate a model, migration, CRUD controller, serializer, tests and routes",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		modelName := args[0]
+		fields := args[1:]
+		softDelete, _ := cmd.Flags().GetBool("soft-delete")
+		options := fileOptions(cmd)
+		modelOptions := generators.ModelOptions{SoftDelete: softDelete}
+		if err := generators.GenerateScaffold(modelName, fields, modelOptions, options); err != nil {
+			log.Fatal(err)
+		}
+		if !options.Pretend {
+			fmt.Printf("Generated scaffold: %s\n", modelName)
+		}
 	},
 }
 
@@ -79,57 +145,125 @@ var generateMigrationCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		migrationName := args[0]
-		if err := generators.GenerateMigration(migrationName); err != nil {
+		options := fileOptions(cmd)
+		if err := generators.GenerateMigration(migrationName, options); err != nil {
 			log.Fatal(err)
 		}
-		fmt.Printf("Generated migration: %s\n", migrationName)
+		if !options.Pretend {
+			fmt.Printf("Generated migration: %s\n", migrationName)
+		}
 	},
 }
 
-var dbCmd = &cobra.Command{
-	Use:   "db",
-	Short: "Database commands",
+var destroyCmd = &cobra.Command{
+	Use:               "destroy",
+	Short:             "Remove files created by generate",
+	PersistentPreRunE: loadInflections,
 }
 
-var dbMigrateCmd = &cobra.Command{
-	Use:   "migrate",
-	Short: "Run database migrations",
-	Run: func(cmd *cobra.Command, args []string) {
-		app := core.NewApplication()
-		if err := app.DB.AutoMigrate(); err != nil {
-			log.Fatal(err)
-		}
-		fmt.Println("Database migrations completed successfully")
-	},
+// destroyCommand создает подкоманду destroy для генератора kind
+func destroyCommand(kind string, destroy func(name string, options generators.DestroyOptions) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   kind + " [name]",
+		Short: "Remove a " + kind + " created by generate " + kind,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			force, _ := cmd.Flags().GetBool("force")
+			pretend, _ := cmd.Flags().GetBool("pretend")
+			options := generators.DestroyOptions{Force: force, Pretend: pretend, AppliedVersions: appliedVersions}
+			if err := destroy(args[0], options); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().BoolP("force", "f", false, "remove files modified after generation and migrations that are still applied")
+	cmd.Flags().BoolP("pretend", "p", false, "only print what would be removed, without removing files")
+	return cmd
 }
 
-var dbSeedCmd = &cobra.Command{
-	Use:   "seed",
-	Short: "Seed the database with sample data",
-	Run: func(cmd *cobra.Command, args []string) {
-		app := core.NewApplication()
-		if err := generators.SeedDatabase(app.DB); err != nil {
-			log.Fatal(err)
+// appliedVersions читает schema_migrations базы приложения в текущей папке.
+// Если базы sqlite еще нет, миграции считаются неприменными.
+func appliedVersions() (map[string]bool, error) {
+	// Configure создает gin.Engine, отладочный вывод которого здесь не нужен
+	gin.SetMode(gin.ReleaseMode)
+	app := core.Configure()
+	if !app.Config.GetBool("database.enabled") {
+		return nil, nil
+	}
+	config, err := app.DatabaseConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.Driver == "sqlite3" {
+		if _, err := os.Stat(config.Database); os.IsNotExist(err) {
+			return nil, nil
 		}
-		fmt.Println("Database seeded successfully")
-	},
+	}
+
+	config.LogLevel = "silent"
+	db, err := database.NewDatabase(config)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if !db.HasTable(&database.SchemaMigration{}) {
+		return nil, nil
+	}
+	var rows []database.SchemaMigration
+	if err := db.DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	versions := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		versions[row.Version] = true
+	}
+	return versions, nil
 }
 
 func init() {
 	rootCmd.AddCommand(serverCmd)
+	addFileFlags(newCmd.Flags())
+	newCmd.Flags().String("framework-path", "", "local go-rails sources to use via replace in go.mod (default $GORAILS_FRAMEWORK_PATH)")
+	newCmd.Flags().Bool("skip-tidy", false, "do not run go mod tidy in the new application")
+	newCmd.Flags().StringP("database", "d", "sqlite3", "database driver for config.yaml: sqlite3, mysql or postgres")
+	newCmd.Flags().Bool("api", false, "API-only application without views and static assets")
+	newCmd.Flags().Bool("skip-auth", false, "do not mount the framework's users and authentication routes")
+	newCmd.Flags().Bool("minimal", false, "API-only application without auth, inflections.yml and seeds")
+	newCmd.Flags().StringP("template", "m", "", "YAML or Go application template to apply after generation")
 	rootCmd.AddCommand(newCmd)
 
+	generateModelCmd.Flags().Bool("soft-delete", false, "embed models.SoftDeletable so Delete only marks records as deleted")
+	generateScaffoldCmd.Flags().Bool("soft-delete", false, "embed models.SoftDeletable so Delete only marks records as deleted")
+
+	addFileFlags(generateCmd.PersistentFlags())
+
 	generateCmd.AddCommand(generateControllerCmd)
 	generateCmd.AddCommand(generateModelCmd)
 	generateCmd.AddCommand(generateMigrationCmd)
+	generateCmd.AddCommand(generateScaffoldCmd)
 	rootCmd.AddCommand(generateCmd)
 
-	dbCmd.AddCommand(dbMigrateCmd)
-	dbCmd.AddCommand(dbSeedCmd)
-	rootCmd.AddCommand(dbCmd)
+	destroyCmd.AddCommand(destroyCommand("controller", generators.DestroyController))
+	destroyCmd.AddCommand(destroyCommand("model", generators.DestroyModel))
+	destroyCmd.AddCommand(destroyCommand("migration", generators.DestroyMigration))
+	destroyCmd.AddCommand(destroyCommand("scaffold", generators.DestroyScaffold))
+	rootCmd.AddCommand(destroyCmd)
+
+	rootCmd.AddCommand(cli.DBCmd)
+	rootCmd.AddCommand(cli.RoutesCmd)
 }
 
 func main() {
+	// Внутри приложения команды db и routes выполняются его собственным
+	// бинарником, иначе gorails не увидит миграции и маршруты приложения
+	if cli.Delegated(os.Args[1:]) {
+		if err := cli.Delegate(os.Args[1:]); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
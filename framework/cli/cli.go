@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go-rails/framework/core"
+
+	"github.com/spf13/cobra"
+)
+
+// appCmd корневая команда бинарника приложения
+var appCmd = &cobra.Command{
+	Use:   filepath.Base(os.Args[0]),
+	Short: "Go-Rails application",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := core.New(appOptions)
+		if err != nil {
+			log.Fatalf("Failed to boot application: %v", err)
+		}
+		if err := app.Run(); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// appOptions настройки приложения, переданные в Execute
+var appOptions core.Options
+
+// Execute запускает приложение: без аргументов стартует сервер,
+// с аргументами выполняет команды фреймворка (например, db migrate).
+// Вызывается из main.go приложения с его маршрутами, чтобы команды
+// видели его миграции и маршруты.
+func Execute(options core.Options) error {
+	appOptions = options
+	appCmd.AddCommand(DBCmd)
+	appCmd.AddCommand(RoutesCmd)
+	return appCmd.Execute()
+}
+
+// Delegated сообщает, должна ли команда выполняться бинарником приложения
+func Delegated(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "db", "routes":
+		return InApp()
+	default:
+		return false
+	}
+}
+
+// InApp проверяет, что текущая папка является приложением Go-Rails
+// со своими миграциями, а не исходниками самого фреймворка
+func InApp() bool {
+	if _, err := os.Stat(filepath.Join("db", "migrate")); err != nil {
+		return false
+	}
+	module := modulePath("go.mod")
+	return module != "" && module != "go-rails"
+}
+
+// Delegate выполняет команду внутри приложения через go run,
+// чтобы в процесс попали миграции и модели приложения
+func Delegate(args []string) error {
+	cmd := exec.Command("go", append([]string{"run", "."}, args...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// modulePath возвращает имя модуля из go.mod
+func modulePath(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-rails/framework/core"
+	"go-rails/framework/database"
+
+	"github.com/spf13/cobra"
+)
+
+// DBCmd группа команд для работы с базой данных
+var DBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database commands",
+	// Без базы данных app.DB равен nil, и команды не могут работать
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if !core.Configure().Config.GetBool("database.enabled") {
+			cmd.SilenceUsage = true
+			return errors.New("database is disabled in config")
+		}
+		return nil
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Run database migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.NewApplication()
+		migrator := database.NewMigrator(app.DB)
+
+		var reverted, applied []database.Migration
+		var err error
+		if target, _ := cmd.Flags().GetString("to"); target != "" {
+			reverted, applied, err = migrator.MigrateTo(target)
+		} else {
+			applied, err = migrator.Migrate()
+		}
+		printMigrations(reverted, applied)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(reverted) == 0 && len(applied) == 0 {
+			fmt.Println("No pending migrations")
+			return
+		}
+		dumpSchema(app)
+		fmt.Println("Database migrations completed successfully")
+	},
+}
+
+var dbRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back the last migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		steps, _ := cmd.Flags().GetInt("step")
+		app := core.NewApplication()
+		reverted, err := database.NewMigrator(app.DB).Rollback(steps)
+		printMigrations(reverted, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to roll back")
+			return
+		}
+		dumpSchema(app)
+	},
+}
+
+var dbRedoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Roll back and re-apply the last migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		steps, _ := cmd.Flags().GetInt("step")
+		app := core.NewApplication()
+		reverted, applied, err := database.NewMigrator(app.DB).Redo(steps)
+		printMigrations(reverted, applied)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dumpSchema(app)
+	},
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.NewApplication()
+		statuses, err := database.NewMigrator(app.DB).Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tVERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt, name := "down", "", s.Name
+			if s.Applied {
+				state = "up"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Missing {
+				name += " (NO FILE)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", state, s.Version, name, appliedAt)
+		}
+		w.Flush()
+	},
+}
+
+var dbSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Dump or load the database schema",
+}
+
+var dbSchemaDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Write the current schema to db/schema.sql",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.NewApplication()
+		path := schemaPath(cmd, app)
+		if err := writeSchema(app, path); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Schema dumped to %s\n", path)
+	},
+}
+
+var dbSchemaLoadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Recreate the schema from db/schema.sql",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.NewApplication()
+		path := schemaPath(cmd, app)
+		if err := loadSchema(app, path); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Schema loaded from %s\n", path)
+	},
+}
+
+var dbSeedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Seed the database with sample data",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.NewApplication()
+		if err := seedDatabase(app); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Database seeded successfully")
+	},
+}
+
+var dbPurgeDeletedCmd = &cobra.Command{
+	Use:   "purge-deleted",
+	Short: "Permanently delete soft-deleted records",
+	Long: `Permanently delete records whose deleted_at is older than --older-than
+from every table with a deleted_at column, or only from --table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		age, err := parseAge(olderThan)
+		if err != nil {
+			log.Fatalf("Invalid --older-than: %v", err)
+		}
+
+		app := core.NewApplication()
+		tables, _ := cmd.Flags().GetStringSlice("table")
+		if len(tables) == 0 {
+			if tables, err = app.DB.SoftDeleteTables(); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		before := time.Now().Add(-age)
+		for _, table := range tables {
+			purged, err := app.DB.PurgeDeleted(table, before)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("%s: purged %d records\n", table, purged)
+		}
+	},
+}
+
+func init() {
+	dbMigrateCmd.Flags().String("to", "", "migrate up or down to the given VERSION (0 reverts everything)")
+	dbRollbackCmd.Flags().Int("step", 1, "number of migrations to roll back")
+	dbRedoCmd.Flags().Int("step", 1, "number of migrations to redo")
+
+	DBCmd.AddCommand(dbMigrateCmd)
+	DBCmd.AddCommand(dbRollbackCmd)
+	DBCmd.AddCommand(dbRedoCmd)
+	DBCmd.AddCommand(dbStatusCmd)
+	DBCmd.AddCommand(dbSeedCmd)
+
+	dbPurgeDeletedCmd.Flags().String("older-than", "30d", "purge records deleted earlier than this age (e.g. 30d, 12h)")
+	dbPurgeDeletedCmd.Flags().StringSlice("table", nil, "purge only these tables")
+	DBCmd.AddCommand(dbPurgeDeletedCmd)
+
+	dbSchemaCmd.PersistentFlags().String("file", "", "schema file (default db/schema.sql)")
+	dbSchemaCmd.AddCommand(dbSchemaDumpCmd)
+	dbSchemaCmd.AddCommand(dbSchemaLoadCmd)
+	DBCmd.AddCommand(dbSchemaCmd)
+}
+
+// parseAge разбирает возраст вида "30d" или длительность Go ("12h")
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return database.ParseDuration(value)
+}
+
+// printMigrations выводит откаченные и примененные миграции в порядке выполнения
+func printMigrations(reverted, applied []database.Migration) {
+	for _, m := range reverted {
+		fmt.Printf("== %s %s: reverted\n", m.Version, m.Name)
+	}
+	for _, m := range applied {
+		fmt.Printf("== %s %s: migrated\n", m.Version, m.Name)
+	}
+}
+
+// schemaPath возвращает путь к файлу схемы из флага --file или по умолчанию
+func schemaPath(cmd *cobra.Command, app *core.Application) string {
+	if path, _ := cmd.Flags().GetString("file"); path != "" {
+		return path
+	}
+	return filepath.Join(app.RootPath, "db", "schema.sql")
+}
+
+// writeSchema сохраняет схему базы данных в файл
+func writeSchema(app *core.Application, path string) error {
+	schema, err := app.DB.DumpSchema()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(schema), 0644)
+}
+
+// loadSchema воссоздает схему из файла
+func loadSchema(app *core.Application, path string) error {
+	schema, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return app.DB.LoadSchema(string(schema))
+}
+
+// seedDatabase загружает db/seeds для текущего окружения
+func seedDatabase(app *core.Application) error {
+	dir := filepath.Join(app.RootPath, "db", "seeds")
+	return database.NewSeeder(app.DB, dir, app.Env).Run()
+}
+
+// dumpSchema обновляет db/schema.sql после изменения схемы миграциями
+func dumpSchema(app *core.Application) {
+	path := filepath.Join(app.RootPath, "db", "schema.sql")
+	if err := writeSchema(app, path); err != nil {
+		log.Printf("Warning: could not dump schema: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"go-rails/framework/core"
+	"go-rails/framework/database"
+
+	"github.com/spf13/cobra"
+)
+
+var dbCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create the configured database",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := core.Configure().DatabaseConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := database.Create(config); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Created database %s\n", config.Database)
+	},
+}
+
+var dbDropCmd = &cobra.Command{
+	Use:   "drop",
+	Short: "Drop the configured database",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.Configure()
+		config, err := app.DatabaseConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := checkDestructive(cmd, app, config); err != nil {
+			log.Fatal(err)
+		}
+		if err := database.Drop(config); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Dropped database %s\n", config.Database)
+	},
+}
+
+var dbSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Create the database, load the schema and seed it",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.Configure()
+		if err := setupDatabase(app); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Database setup completed successfully")
+	},
+}
+
+var dbResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Drop and recreate the database, then load the schema and seed it",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := core.Configure()
+		config, err := app.DatabaseConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := checkDestructive(cmd, app, config); err != nil {
+			log.Fatal(err)
+		}
+		if err := database.Drop(config); err != nil {
+			log.Fatal(err)
+		}
+		if err := setupDatabase(app); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Database reset completed successfully")
+	},
+}
+
+func init() {
+	dbDropCmd.Flags().Bool("force", false, "allow dropping the database in production")
+	dbResetCmd.Flags().Bool("force", false, "allow resetting the database in production")
+
+	DBCmd.AddCommand(dbCreateCmd)
+	DBCmd.AddCommand(dbDropCmd)
+	DBCmd.AddCommand(dbSetupCmd)
+	DBCmd.AddCommand(dbResetCmd)
+}
+
+// checkDestructive запрещает удаление базы в production без флага --force
+func checkDestructive(cmd *cobra.Command, app *core.Application, config database.Config) error {
+	force, _ := cmd.Flags().GetBool("force")
+	if app.Env == "production" && !force {
+		return fmt.Errorf("refusing to drop the %s database in production; pass --force to continue",
+			config.Database)
+	}
+	return nil
+}
+
+// setupDatabase создает базу, загружает db/schema.sql (или прогоняет миграции,
+// если файла схемы нет) и заполняет её начальными данными
+func setupDatabase(app *core.Application) error {
+	config, err := app.DatabaseConfig()
+	if err != nil {
+		return err
+	}
+	if err := database.Create(config); err != nil {
+		return err
+	}
+
+	db, err := database.NewDatabase(config)
+	if err != nil {
+		return err
+	}
+	app.DB = db
+
+	path := filepath.Join(app.RootPath, "db", "schema.sql")
+	if _, err := os.Stat(path); err == nil {
+		if err := loadSchema(app, path); err != nil {
+			return err
+		}
+		fmt.Printf("Schema loaded from %s\n", path)
+	} else {
+		applied, err := database.NewMigrator(db).Migrate()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations\n", len(applied))
+	}
+
+	return seedDatabase(app)
+}
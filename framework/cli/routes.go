@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"go-rails/framework/core"
+
+	"github.com/spf13/cobra"
+)
+
+// RoutesCmd выводит маршруты приложения; база данных для этого не нужна
+var RoutesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "List all application routes",
+	Run: func(cmd *cobra.Command, args []string) {
+		options := appOptions
+		options.RoutesOnly = true
+		app, err := core.New(options)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "METHOD\tPATH\tHANDLER")
+		for _, route := range app.Router.Routes() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", route.Method, route.Path, route.Handler)
+		}
+		w.Flush()
+	},
+}
@@ -8,6 +8,7 @@ import (
 
 	"go-rails/framework/database"
 	"go-rails/framework/http/router"
+	"go-rails/framework/inflector"
 	"go-rails/framework/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -23,8 +24,53 @@ type Application struct {
 	Env      string
 }
 
-// NewApplication создает новое приложение
+// Options управляет загрузкой приложения
+type Options struct {
+	// SkipDatabase загружает приложение без подключения к базе данных,
+	// например для сервисов без БД или команды routes.
+	// То же самое включает ключ database.enabled: false в config.yaml.
+	SkipDatabase bool
+
+	// RoutesOnly загружает приложение только ради списка маршрутов (gorails routes):
+	// база не подключается, но маршруты, которым она нужна, тоже регистрируются.
+	RoutesOnly bool
+
+	// Routes маршруты приложения, обычно routes.Draw из его main.go.
+	// Если не заданы, подключаются маршруты, зарегистрированные через router.Draw,
+	// а без них — встроенные маршруты фреймворка.
+	Routes router.DrawFunc
+}
+
+// NewApplication создает новое приложение и завершает процесс при ошибке загрузки
 func NewApplication() *Application {
+	app, err := New(Options{})
+	if err != nil {
+		log.Fatalf("Failed to boot application: %v", err)
+	}
+	return app
+}
+
+// New создает новое приложение и возвращает ошибку вместо завершения процесса
+func New(options Options) (*Application, error) {
+	app := Configure()
+	if options.RoutesOnly {
+		// Обработчики не вызываются, поэтому достаточно базы без соединения
+		app.DB = &database.Database{}
+	} else if !options.SkipDatabase && app.Config.GetBool("database.enabled") {
+		if err := app.setupDatabase(); err != nil {
+			return nil, err
+		}
+	}
+	app.setupMiddleware()
+	app.setupRoutes(options.Routes)
+
+	return app, nil
+}
+
+// Configure создает приложение с загруженной конфигурацией,
+// но без подключения к базе данных и маршрутов.
+// Используется командами, которые управляют самой базой (db create, db drop).
+func Configure() *Application {
 	app := &Application{
 		Router:   gin.Default(),
 		Config:   viper.New(),
@@ -33,9 +79,6 @@ func NewApplication() *Application {
 	}
 
 	app.setupConfig()
-	app.setupDatabase()
-	app.setupMiddleware()
-	app.setupRoutes()
 
 	return app
 }
@@ -52,28 +95,27 @@ func (app *Application) setupConfig() {
 	app.Config.SetDefault("server.host", "localhost")
 	app.Config.SetDefault("database.driver", "sqlite3")
 	app.Config.SetDefault("database.database", "app.db")
+	app.Config.SetDefault("database.enabled", true)
+	app.Config.SetDefault("database_selector.delay", "2s")
 
 	if err := app.Config.ReadInConfig(); err != nil {
 		log.Printf("Warning: Could not read config file: %v", err)
 	}
-}
 
-// setupDatabase настраивает базу данных
-func (app *Application) setupDatabase() {
-	dbConfig := database.Config{
-		Driver:   app.Config.GetString("database.driver"),
-		Host:     app.Config.GetString("database.host"),
-		Port:     app.Config.GetString("database.port"),
-		Database: app.Config.GetString("database.database"),
-		Username: app.Config.GetString("database.username"),
-		Password: app.Config.GetString("database.password"),
+	// Собственные правила склонения, те же, что видят генераторы
+	if err := inflector.LoadFile(filepath.Join(app.RootPath, "config", "inflections.yml")); err != nil {
+		log.Printf("Warning: %v", err)
 	}
+}
 
-	var err error
-	app.DB, err = database.NewDatabase(dbConfig)
+// setupDatabase настраивает базу данных
+func (app *Application) setupDatabase() error {
+	db, err := app.connectDatabases()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return err
 	}
+	app.DB = db
+	return nil
 }
 
 // setupMiddleware настраивает middleware
@@ -87,14 +129,44 @@ func (app *Application) setupMiddleware() {
 	// Recovery
 	app.Router.Use(middleware.Recovery())
 
-	// Статические файлы
-	app.Router.Static("/assets", filepath.Join(app.RootPath, "public", "assets"))
-	app.Router.StaticFile("/favicon.ico", filepath.Join(app.RootPath, "public", "favicon.ico"))
+	// Чтение из основной базы сразу после записи, если есть реплики
+	if app.DB != nil && app.DB.HasReplicas() {
+		app.Router.Use(middleware.DatabaseSelector(app.Config.GetDuration("database_selector.delay")))
+	}
+
+	// Статические файлы; API-приложению (server.api_only) они не нужны
+	if !app.Config.GetBool("server.api_only") {
+		app.Router.Static("/assets", filepath.Join(app.RootPath, "public", "assets"))
+		app.Router.StaticFile("/favicon.ico", filepath.Join(app.RootPath, "public", "favicon.ico"))
+	}
 }
 
 // setupRoutes настраивает маршруты
-func (app *Application) setupRoutes() {
-	router.SetupRoutes(app.Router, app.DB)
+func (app *Application) setupRoutes(routes router.DrawFunc) {
+	// Проверки для оркестратора: процесс жив / готов принимать запросы
+	app.Router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+	app.Router.GET("/ready", app.readiness)
+
+	if routes != nil {
+		routes(app.Router, app.DB)
+		return
+	}
+	router.Mount(app.Router, app.DB)
+}
+
+// readiness отвечает 503, пока база данных недоступна
+func (app *Application) readiness(c *gin.Context) {
+	if app.DB == nil {
+		c.JSON(200, gin.H{"status": "ok", "database": "disabled"})
+		return
+	}
+	if err := app.DB.Ping(); err != nil {
+		c.JSON(503, gin.H{"status": "unavailable", "database": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok", "database": "ok"})
 }
 
 // Run запускает приложение
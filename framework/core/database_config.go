@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go-rails/framework/database"
+
+	"github.com/spf13/viper"
+)
+
+// primaryDatabase имя основной базы в секции databases
+const primaryDatabase = "primary"
+
+// DatabaseConfig возвращает конфигурацию основной базы данных: секцию
+// databases.primary, а если её нет — секцию database файла config.yaml.
+// Переменная окружения DATABASE_URL (или ключ url) задает подключение
+// целиком; остальные ключи секции дополняют её.
+func (app *Application) DatabaseConfig() (database.Config, error) {
+	prefix := app.databasePrefix(primaryDatabase)
+	config := readDatabaseConfig(app.Config, prefix)
+
+	rawURL := os.Getenv("DATABASE_URL")
+	if rawURL == "" {
+		rawURL = app.Config.GetString(prefix + "url")
+	}
+	if rawURL == "" {
+		return config, nil
+	}
+
+	fromURL, err := database.ParseURL(rawURL)
+	if err != nil {
+		return database.Config{}, err
+	}
+	return mergeDatabaseConfig(config, fromURL), nil
+}
+
+// connectDatabases подключает основную базу, её реплики и именованные базы
+// из секции databases
+func (app *Application) connectDatabases() (*database.Database, error) {
+	config, err := app.DatabaseConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %v", err)
+	}
+
+	primary, err := app.connect(primaryDatabase, config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range app.namedDatabases() {
+		prefix := app.databasePrefix(name)
+		named, err := app.connect(name, readDatabaseConfig(app.Config, prefix))
+		if err != nil {
+			return nil, err
+		}
+		primary.Attach(name, named)
+	}
+	return primary, nil
+}
+
+// connect открывает базу и подключает к ней реплики из ключа replicas
+func (app *Application) connect(name string, config database.Config) (*database.Database, error) {
+	db, err := database.NewDatabase(config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+
+	replicas, err := app.replicaConfigs(app.databasePrefix(name), config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	for i, replicaConfig := range replicas {
+		replica, err := database.NewDatabase(replicaConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%s replica %d: %v", name, i+1, err)
+		}
+		db.AddReplicas(replica)
+	}
+	return db, nil
+}
+
+// databasePrefix возвращает префикс ключей конфигурации для базы name
+func (app *Application) databasePrefix(name string) string {
+	if name == primaryDatabase && !app.Config.IsSet("databases."+primaryDatabase) {
+		return "database."
+	}
+	return "databases." + name + "."
+}
+
+// namedDatabases возвращает имена дополнительных баз из секции databases
+func (app *Application) namedDatabases() []string {
+	var names []string
+	for name := range app.Config.GetStringMap("databases") {
+		if name != primaryDatabase {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// replicaConfigs читает список replicas; каждая реплика наследует
+// незаданные параметры от своей основной базы
+func (app *Application) replicaConfigs(prefix string, parent database.Config) ([]database.Config, error) {
+	raw := app.Config.Get(prefix + "replicas")
+	if raw == nil {
+		return nil, nil
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%sreplicas must be a list", prefix)
+	}
+
+	configs := make([]database.Config, 0, len(entries))
+	for i, entry := range entries {
+		values, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%sreplicas[%d] must be a map", prefix, i)
+		}
+		sub := viper.New()
+		if err := sub.MergeConfigMap(values); err != nil {
+			return nil, err
+		}
+		configs = append(configs, mergeDatabaseConfig(parent, readDatabaseConfig(sub, "")))
+	}
+	return configs, nil
+}
+
+// readDatabaseConfig читает конфигурацию базы из ключей с префиксом prefix
+func readDatabaseConfig(v *viper.Viper, prefix string) database.Config {
+	foreignKeys := true
+	if v.IsSet(prefix + "sqlite.foreign_keys") {
+		foreignKeys = v.GetBool(prefix + "sqlite.foreign_keys")
+	}
+	busyTimeout := 5 * time.Second
+	if v.IsSet(prefix + "sqlite.busy_timeout") {
+		busyTimeout = v.GetDuration(prefix + "sqlite.busy_timeout")
+	}
+
+	return database.Config{
+		Driver:          v.GetString(prefix + "driver"),
+		Host:            v.GetString(prefix + "host"),
+		Port:            v.GetString(prefix + "port"),
+		Database:        v.GetString(prefix + "database"),
+		Username:        v.GetString(prefix + "username"),
+		Password:        v.GetString(prefix + "password"),
+		SSLMode:         v.GetString(prefix + "sslmode"),
+		SSLCert:         v.GetString(prefix + "sslcert"),
+		SSLKey:          v.GetString(prefix + "sslkey"),
+		SSLRootCert:     v.GetString(prefix + "sslrootcert"),
+		Charset:         v.GetString(prefix + "charset"),
+		Collation:       v.GetString(prefix + "collation"),
+		Timezone:        v.GetString(prefix + "timezone"),
+		MaxIdleConns:    v.GetInt(prefix + "max_idle_conns"),
+		MaxOpenConns:    v.GetInt(prefix + "max_open_conns"),
+		ConnMaxLifetime: v.GetDuration(prefix + "conn_max_lifetime"),
+		ConnectTimeout:  v.GetDuration(prefix + "connect_timeout"),
+		LogLevel:        v.GetString(prefix + "log_level"),
+		Params:          v.GetStringMapString(prefix + "params"),
+		SQLite: database.SQLiteConfig{
+			JournalMode:     v.GetString(prefix + "sqlite.journal_mode"),
+			BusyTimeout:     busyTimeout,
+			ForeignKeys:     foreignKeys,
+			Synchronous:     v.GetString(prefix + "sqlite.synchronous"),
+			SerializeWrites: v.GetBool(prefix + "sqlite.serialize_writes"),
+		},
+		Retry: database.RetryConfig{
+			Attempts:        v.GetInt(prefix + "retry.attempts"),
+			InitialInterval: v.GetDuration(prefix + "retry.interval"),
+			MaxInterval:     v.GetDuration(prefix + "retry.max_interval"),
+		},
+	}
+}
+
+// mergeDatabaseConfig дополняет незаданные поля override значениями из base
+func mergeDatabaseConfig(base, override database.Config) database.Config {
+	merged := override
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&merged.Driver, base.Driver)
+	fill(&merged.Host, base.Host)
+	fill(&merged.Port, base.Port)
+	fill(&merged.Database, base.Database)
+	fill(&merged.Username, base.Username)
+	fill(&merged.Password, base.Password)
+	fill(&merged.SSLMode, base.SSLMode)
+	fill(&merged.SSLCert, base.SSLCert)
+	fill(&merged.SSLKey, base.SSLKey)
+	fill(&merged.SSLRootCert, base.SSLRootCert)
+	fill(&merged.Charset, base.Charset)
+	fill(&merged.Collation, base.Collation)
+	fill(&merged.Timezone, base.Timezone)
+	fill(&merged.LogLevel, base.LogLevel)
+
+	if merged.MaxIdleConns == 0 {
+		merged.MaxIdleConns = base.MaxIdleConns
+	}
+	if merged.MaxOpenConns == 0 {
+		merged.MaxOpenConns = base.MaxOpenConns
+	}
+	if merged.ConnMaxLifetime == 0 {
+		merged.ConnMaxLifetime = base.ConnMaxLifetime
+	}
+	if merged.ConnectTimeout == 0 {
+		merged.ConnectTimeout = base.ConnectTimeout
+	}
+	if merged.Driver == base.Driver {
+		merged.SQLite = base.SQLite
+	}
+	if merged.Retry == (database.RetryConfig{}) {
+		merged.Retry = base.Retry
+	}
+
+	params := make(map[string]string, len(base.Params)+len(merged.Params))
+	for key, value := range base.Params {
+		params[key] = value
+	}
+	for key, value := range merged.Params {
+		params[key] = value
+	}
+	merged.Params = params
+	return merged
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
@@ -18,43 +19,137 @@ type Config struct {
 	Database string
 	Username string
 	Password string
+
+	// SSL: disable, require, verify-ca, verify-full (для mysql также preferred)
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+
+	Charset   string // utf8 по умолчанию для mysql; client_encoding для postgres
+	Collation string // только mysql
+	Timezone  string // Local по умолчанию для mysql; TimeZone сессии для postgres
+
+	// Пул соединений; 0 означает значение по умолчанию
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration
+	ConnectTimeout  time.Duration
+
+	// LogLevel: silent, error или info (по умолчанию — все SQL-запросы)
+	LogLevel string
+
+	// Params дополнительные параметры DSN, специфичные для драйвера
+	Params map[string]string
+
+	// SQLite настройки, применяемые только драйвером sqlite3
+	SQLite SQLiteConfig
+
+	// Retry повторные попытки подключения, пока база не станет доступна
+	Retry RetryConfig
 }
 
-// Database представляет соединение с базой данных
+// SQLiteConfig содержит PRAGMA-настройки sqlite3, которые применяются
+// к каждому соединению пула через параметры DSN
+type SQLiteConfig struct {
+	JournalMode string        // DELETE, WAL, MEMORY и т.д.
+	BusyTimeout time.Duration // сколько ждать снятия блокировки вместо "database is locked"
+	ForeignKeys bool          // PRAGMA foreign_keys = ON
+	Synchronous string        // OFF, NORMAL, FULL, EXTRA
+
+	// SerializeWrites ограничивает пул одним соединением, чтобы записи
+	// выполнялись строго последовательно
+	SerializeWrites bool
+}
+
+const (
+	defaultMaxIdleConns = 10
+	defaultMaxOpenConns = 100
+)
+
+// Database представляет соединение с базой данных.
+// Запись всегда идет через встроенный *gorm.DB (основная база),
+// чтение через Find/First/Where распределяется по репликам, если они есть.
 type Database struct {
 	*gorm.DB
+
+	replicas []*gorm.DB
+	next     *uint32
+	named    map[string]*Database
+	txDepth  int // 0 — вне транзакции, 1 — транзакция, >1 — savepoint
 }
 
 // NewDatabase создает новое соединение с базой данных
 func NewDatabase(config Config) (*Database, error) {
-	var dsn string
-
-	switch config.Driver {
-	case "sqlite3":
-		dsn = config.Database
-	case "mysql":
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&parseTime=True&loc=Local",
-			config.Username, config.Password, config.Host, config.Port, config.Database)
-	case "postgres":
-		dsn = fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable",
-			config.Host, config.Port, config.Username, config.Database, config.Password)
-	default:
-		return nil, fmt.Errorf("unsupported database driver: %s", config.Driver)
+	dsn, err := buildDSN(config)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := gorm.Open(config.Driver, dsn)
+	var db *gorm.DB
+	err = withRetry(config.Retry, func() error {
+		db, err = gorm.Open(config.Driver, dsn)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
 
 	// Настройка GORM
-	db.LogMode(true)
-	db.DB().SetMaxIdleConns(10)
-	db.DB().SetMaxOpenConns(100)
+	configureLogging(db, config.LogLevel)
+	configurePool(db, config)
 
 	return &Database{DB: db}, nil
 }
 
+// configureLogging включает вывод SQL в соответствии с уровнем логирования
+func configureLogging(db *gorm.DB, level string) {
+	switch level {
+	case "silent":
+		db.LogMode(false)
+	case "error":
+		// Режим GORM по умолчанию: выводятся только ошибки
+	default:
+		db.LogMode(true)
+	}
+}
+
+// configurePool настраивает пул соединений
+func configurePool(db *gorm.DB, config Config) {
+	maxIdle := config.MaxIdleConns
+	if maxIdle == 0 {
+		maxIdle = defaultMaxIdleConns
+	}
+	maxOpen := config.MaxOpenConns
+	if maxOpen == 0 {
+		maxOpen = defaultMaxOpenConns
+	}
+
+	lifetime := config.ConnMaxLifetime
+
+	if config.Driver == "sqlite3" {
+		if config.SQLite.SerializeWrites {
+			maxOpen = 1
+		}
+		// База в памяти живет, пока открыто хотя бы одно соединение
+		if isMemorySQLite(config.Database) {
+			lifetime = 0
+			if maxIdle < 1 {
+				maxIdle = 1
+			}
+		}
+	}
+	if maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+
+	db.DB().SetMaxIdleConns(maxIdle)
+	db.DB().SetMaxOpenConns(maxOpen)
+	if lifetime > 0 {
+		db.DB().SetConnMaxLifetime(lifetime)
+	}
+}
+
 // AutoMigrate выполняет автоматическую миграцию моделей
 func (db *Database) AutoMigrate(models ...interface{}) error {
 	return db.DB.AutoMigrate(models...).Error
@@ -79,12 +174,12 @@ func (db *Database) HasTable(model interface{}) bool {
 
 // Find находит записи
 func (db *Database) Find(out interface{}, where ...interface{}) *gorm.DB {
-	return db.DB.Find(out, where...)
+	return db.reader().Find(out, where...)
 }
 
 // First находит первую запись
 func (db *Database) First(out interface{}, where ...interface{}) *gorm.DB {
-	return db.DB.First(out, where...)
+	return db.reader().First(out, where...)
 }
 
 // Create создает запись
@@ -92,8 +187,13 @@ func (db *Database) Create(value interface{}) *gorm.DB {
 	return db.DB.Create(value)
 }
 
-// Save сохраняет запись
+// Save сохраняет запись. Для существующей записи с полем LockVersion
+// проверяет версию и возвращает *StaleObjectError, если запись уже изменили.
 func (db *Database) Save(value interface{}) *gorm.DB {
+	scope := db.NewScope(value)
+	if field, ok := scope.FieldByName("LockVersion"); ok && !scope.PrimaryKeyZero() {
+		return db.saveLocked(scope, field)
+	}
 	return db.DB.Save(value)
 }
 
@@ -102,7 +202,9 @@ func (db *Database) Delete(value interface{}, where ...interface{}) *gorm.DB {
 	return db.DB.Delete(value, where...)
 }
 
-// Where добавляет условие WHERE
+// Where добавляет условие WHERE. Запрос строится на основной базе, потому что
+// за ним может последовать запись (Delete, Update, Updates); для чтения
+// с реплики используйте Reader().Where(...)
 func (db *Database) Where(query interface{}, args ...interface{}) *gorm.DB {
 	return db.DB.Where(query, args...)
 }
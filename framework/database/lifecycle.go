@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Create создает базу данных из конфигурации.
+// Для mysql и postgres подключается к серверу без целевой базы,
+// для sqlite3 создает файл.
+func Create(config Config) error {
+	switch config.Driver {
+	case "sqlite3":
+		if isMemorySQLite(config.Database) {
+			return nil
+		}
+		// DSN с PRAGMA-параметрами, чтобы, например, journal_mode=WAL
+		// был записан в файл уже при создании
+		db, err := gorm.Open("sqlite3", sqliteDSN(config))
+		if err != nil {
+			return fmt.Errorf("failed to create database %s: %v", config.Database, err)
+		}
+		// Файл sqlite создается лениво, при первом обращении
+		if err := db.DB().Ping(); err != nil {
+			db.Close()
+			return fmt.Errorf("failed to create database %s: %v", config.Database, err)
+		}
+		return db.Close()
+	case "mysql":
+		return execOnServer(config, mysqlCreateDatabase(config))
+	case "postgres":
+		exists, err := postgresDatabaseExists(config)
+		if err != nil || exists {
+			return err
+		}
+		return execOnServer(config, fmt.Sprintf("CREATE DATABASE %q", config.Database))
+	default:
+		return fmt.Errorf("unsupported database driver: %s", config.Driver)
+	}
+}
+
+// Drop удаляет базу данных из конфигурации. Отсутствие базы не считается ошибкой.
+func Drop(config Config) error {
+	switch config.Driver {
+	case "sqlite3":
+		if isMemorySQLite(config.Database) {
+			return nil
+		}
+		for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+			if err := os.Remove(config.Database + suffix); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to drop database %s: %v", config.Database, err)
+			}
+		}
+		return nil
+	case "mysql":
+		return execOnServer(config, fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", config.Database))
+	case "postgres":
+		return execOnServer(config, fmt.Sprintf("DROP DATABASE IF EXISTS %q", config.Database))
+	default:
+		return fmt.Errorf("unsupported database driver: %s", config.Driver)
+	}
+}
+
+// mysqlCreateDatabase формирует CREATE DATABASE с кодировкой и сравнением
+// из конфигурации; по умолчанию используется utf8mb4
+func mysqlCreateDatabase(config Config) string {
+	charset := config.Charset
+	if charset == "" {
+		charset = "utf8mb4"
+	}
+	stmt := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s` CHARACTER SET %s", config.Database, charset)
+	if config.Collation != "" {
+		stmt += " COLLATE " + config.Collation
+	}
+	return stmt
+}
+
+// serverConfig возвращает конфигурацию подключения к серверу без целевой базы
+func serverConfig(config Config) Config {
+	server := config
+	switch config.Driver {
+	case "mysql":
+		server.Database = ""
+	case "postgres":
+		server.Database = "postgres"
+	}
+	return server
+}
+
+// execOnServer выполняет оператор на уровне сервера баз данных
+func execOnServer(config Config, stmt string) error {
+	dsn, err := buildDSN(serverConfig(config))
+	if err != nil {
+		return err
+	}
+
+	db, err := gorm.Open(config.Driver, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s server: %v", config.Driver, err)
+	}
+	defer db.Close()
+
+	if err := db.Exec(stmt).Error; err != nil {
+		return fmt.Errorf("%s: %v", stmt, err)
+	}
+	return nil
+}
+
+// postgresDatabaseExists проверяет наличие базы в pg_database,
+// так как PostgreSQL не поддерживает CREATE DATABASE IF NOT EXISTS
+func postgresDatabaseExists(config Config) (bool, error) {
+	dsn, err := buildDSN(serverConfig(config))
+	if err != nil {
+		return false, err
+	}
+
+	db, err := gorm.Open(config.Driver, dsn)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to %s server: %v", config.Driver, err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.Raw("SELECT count(*) FROM pg_database WHERE datname = ?", config.Database).Row().Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// isMemorySQLite проверяет, что sqlite работает в памяти
+func isMemorySQLite(name string) bool {
+	return name == "" || strings.HasPrefix(name, ":memory:") || strings.Contains(name, "mode=memory")
+}
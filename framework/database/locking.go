@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+// lockVersionColumn колонка модели с полем LockVersion (см. models.Lockable)
+const lockVersionColumn = "lock_version"
+
+// StaleObjectError запись изменили после того, как она была прочитана
+type StaleObjectError struct {
+	Table          string
+	ID             interface{}
+	Version        int // версия, с которой пытались сохранить запись
+	CurrentVersion int // версия в базе
+}
+
+func (e *StaleObjectError) Error() string {
+	return fmt.Sprintf("%s %v is stale: lock_version %d, current %d", e.Table, e.ID, e.Version, e.CurrentVersion)
+}
+
+// saveLocked сохраняет существующую запись с полем LockVersion:
+// UPDATE ... SET ..., lock_version = N+1 WHERE id = ? AND lock_version = N
+func (db *Database) saveLocked(scope *gorm.Scope, field *gorm.Field) *gorm.DB {
+	version := int(field.Field.Int())
+
+	attrs := make(map[string]interface{})
+	for _, f := range scope.Fields() {
+		if f.IsPrimaryKey || !f.IsNormal || f.IsIgnored || (f.Name == "CreatedAt" && f.IsBlank) {
+			continue
+		}
+		attrs[f.DBName] = f.Field.Interface()
+	}
+	attrs[lockVersionColumn] = version + 1
+
+	result := db.DB.Model(scope.Value).
+		Where(scope.Quote(lockVersionColumn)+" = ?", version).
+		Updates(attrs)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result
+	}
+
+	// Ни одна строка не обновлена: запись удалили или сохранили с другой версией
+	field.Set(version)
+	var current int
+	err := db.DB.Table(scope.TableName()).
+		Where(scope.Quote(scope.PrimaryKey())+" = ?", scope.PrimaryKeyValue()).
+		Select(scope.Quote(lockVersionColumn)).Row().Scan(&current)
+	if err != nil {
+		result.Error = gorm.ErrRecordNotFound
+		return result
+	}
+	result.Error = &StaleObjectError{
+		Table:          scope.TableName(),
+		ID:             scope.PrimaryKeyValue(),
+		Version:        version,
+		CurrentVersion: current,
+	}
+	return result
+}
@@ -0,0 +1,374 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MigrationFunc функция, изменяющая схему базы данных
+type MigrationFunc func(db *Database) error
+
+// Migration описывает одну версионированную миграцию
+type Migration struct {
+	Version string // временная метка вида 20060102150405
+	Name    string
+	Up      MigrationFunc
+	Down    MigrationFunc
+}
+
+// SchemaMigration запись о примененной миграции в таблице schema_migrations
+type SchemaMigration struct {
+	Version   string    `gorm:"primary_key;size:32"`
+	Name      string    `gorm:"size:255"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName возвращает имя таблицы
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Migration)
+)
+
+// Register регистрирует миграцию в глобальном реестре.
+// Вызывается из init() сгенерированных файлов в db/migrate.
+func Register(m Migration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if m.Version == "" {
+		panic("database: migration version is empty")
+	}
+	if m.Up == nil {
+		panic(fmt.Sprintf("database: migration %s has no Up function", m.Version))
+	}
+	if _, exists := registry[m.Version]; exists {
+		panic(fmt.Sprintf("database: migration %s registered twice", m.Version))
+	}
+	registry[m.Version] = m
+}
+
+// Migrations возвращает зарегистрированные миграции, упорядоченные по версии
+func Migrations() []Migration {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	migrations := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// IrreversibleMigrationError возвращается при откате миграции без Down
+type IrreversibleMigrationError struct {
+	Version string
+	Name    string
+}
+
+func (e *IrreversibleMigrationError) Error() string {
+	return fmt.Sprintf("migration %s_%s is irreversible: it has no Down function", e.Version, e.Name)
+}
+
+// MigrationStatus состояние миграции для команды db status
+type MigrationStatus struct {
+	Version   string
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Missing   bool // версия есть в schema_migrations, но файла миграции нет
+}
+
+// Migrator применяет миграции и ведет учет в таблице schema_migrations
+type Migrator struct {
+	db         *Database
+	migrations []Migration
+}
+
+// NewMigrator создает мигратор для всех зарегистрированных миграций
+func NewMigrator(db *Database) *Migrator {
+	return &Migrator{db: db, migrations: Migrations()}
+}
+
+// ensureSchemaTable создает таблицу schema_migrations, если её нет
+func (m *Migrator) ensureSchemaTable() error {
+	if m.db.HasTable(&SchemaMigration{}) {
+		return nil
+	}
+	return m.db.CreateTable(&SchemaMigration{})
+}
+
+// appliedVersions возвращает множество уже примененных версий
+func (m *Migrator) appliedVersions() (map[string]SchemaMigration, error) {
+	if err := m.ensureSchemaTable(); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	var rows []SchemaMigration
+	if err := m.db.DB.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+
+	applied := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Pending возвращает миграции, которые еще не были применены
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate применяет все ожидающие миграции по порядку
+func (m *Migrator) Migrate() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		if err := m.up(migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateTo приводит схему к указанной версии: откатывает примененные миграции
+// новее неё и применяет ожидающие миграции до неё включительно.
+// Возвращает откаченные и примененные миграции отдельно; версия "0" откатывает все.
+func (m *Migrator) MigrateTo(version string) (reverted, applied []Migration, err error) {
+	if version != "0" && !m.known(version) {
+		return nil, nil, fmt.Errorf("unknown migration version: %s", version)
+	}
+
+	versions, err := m.appliedVersions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var revert []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := versions[migration.Version]; ok && migration.Version > version {
+			if migration.Down == nil {
+				return nil, nil, &IrreversibleMigrationError{Version: migration.Version, Name: migration.Name}
+			}
+			revert = append(revert, migration)
+		}
+	}
+
+	for _, migration := range revert {
+		if err := m.down(migration); err != nil {
+			return reverted, nil, err
+		}
+		reverted = append(reverted, migration)
+	}
+	for _, migration := range m.migrations {
+		if _, ok := versions[migration.Version]; !ok && migration.Version <= version {
+			if err := m.up(migration); err != nil {
+				return reverted, applied, err
+			}
+			applied = append(applied, migration)
+		}
+	}
+	return reverted, applied, nil
+}
+
+// Rollback откатывает последние steps примененных миграций
+func (m *Migrator) Rollback(steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("rollback steps must be positive, got %d", steps)
+	}
+
+	targets, err := m.lastApplied(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверяем обратимость заранее, чтобы не откатить схему наполовину
+	for _, migration := range targets {
+		if migration.Down == nil {
+			return nil, &IrreversibleMigrationError{Version: migration.Version, Name: migration.Name}
+		}
+	}
+
+	var done []Migration
+	for _, migration := range targets {
+		if err := m.down(migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Redo откатывает и заново применяет последние steps миграций.
+// При ошибке возвращает уже откаченные и заново примененные миграции:
+// откаченные, но не примененные остаются откаченными.
+func (m *Migrator) Redo(steps int) (reverted, applied []Migration, err error) {
+	reverted, err = m.Rollback(steps)
+	if err != nil {
+		return reverted, nil, err
+	}
+
+	for i := len(reverted) - 1; i >= 0; i-- {
+		if err := m.up(reverted[i]); err != nil {
+			return reverted, applied, err
+		}
+		applied = append(applied, reverted[i])
+	}
+	return reverted, applied, nil
+}
+
+// Status возвращает состояние всех известных миграций, упорядоченное по версии
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		statuses = append(statuses, MigrationStatus{
+			Version:   row.Version,
+			Name:      row.Name,
+			Applied:   true,
+			AppliedAt: row.AppliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// lastApplied возвращает до n последних примененных миграций, начиная с самой новой
+func (m *Migrator) lastApplied(n int) ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	if len(versions) > n {
+		versions = versions[:n]
+	}
+
+	byVersion := make(map[string]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	result := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		migration, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("migration %s is applied but its file is missing", version)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// known проверяет, зарегистрирована ли версия
+func (m *Migrator) known(version string) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// up применяет одну миграцию и записывает её версию
+func (m *Migrator) up(migration Migration) error {
+	err := m.run(func(db *Database) error {
+		if err := migration.Up(db); err != nil {
+			return err
+		}
+		return db.DB.Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %s_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// down откатывает одну миграцию и удаляет запись о её версии
+func (m *Migrator) down(migration Migration) error {
+	if migration.Down == nil {
+		return &IrreversibleMigrationError{Version: migration.Version, Name: migration.Name}
+	}
+
+	err := m.run(func(db *Database) error {
+		if err := migration.Down(db); err != nil {
+			return err
+		}
+		return db.DB.Where("version = ?", migration.Version).Delete(&SchemaMigration{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rollback of %s_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// run выполняет fn в транзакции, если диалект поддерживает транзакционный DDL
+func (m *Migrator) run(fn func(db *Database) error) error {
+	if !m.transactionalDDL() {
+		return fn(m.db)
+	}
+	return m.db.Transaction(fn)
+}
+
+// transactionalDDL сообщает, можно ли откатить изменения схемы транзакцией.
+// MySQL неявно фиксирует транзакцию на каждом DDL-операторе.
+func (m *Migrator) transactionalDDL() bool {
+	switch m.db.Dialect().GetName() {
+	case "postgres", "sqlite3":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+)
+
+// AddReplicas подключает реплики только для чтения.
+// Find, First и Reader выполняются на репликах по кругу.
+func (db *Database) AddReplicas(replicas ...*Database) {
+	if db.next == nil {
+		db.next = new(uint32)
+	}
+	for _, replica := range replicas {
+		db.replicas = append(db.replicas, replica.DB)
+	}
+}
+
+// HasReplicas сообщает, подключены ли реплики
+func (db *Database) HasReplicas() bool {
+	return len(db.replicas) > 0
+}
+
+// Primary возвращает копию, которая и читает, и пишет в основную базу.
+// Нужна, чтобы прочитать только что записанные данные, которые еще
+// не успели попасть на реплику.
+func (db *Database) Primary() *Database {
+	return &Database{DB: db.DB, named: db.named}
+}
+
+// Attach регистрирует дополнительную именованную базу
+func (db *Database) Attach(name string, other *Database) {
+	if db.named == nil {
+		db.named = make(map[string]*Database)
+	}
+	db.named[name] = other
+}
+
+// Named возвращает дополнительную базу из секции databases конфигурации
+func (db *Database) Named(name string) (*Database, error) {
+	named, ok := db.named[name]
+	if !ok {
+		return nil, fmt.Errorf("database %q is not configured", name)
+	}
+	return named, nil
+}
+
+// Close закрывает соединения основной базы, реплик и именованных баз
+func (db *Database) Close() error {
+	for _, replica := range db.replicas {
+		replica.Close()
+	}
+	for _, named := range db.named {
+		named.Close()
+	}
+	return db.DB.Close()
+}
+
+// Reader возвращает соединение для чтения: реплику по кругу или основную базу,
+// если реплик нет. Записи через него выполнять нельзя.
+func (db *Database) Reader() *gorm.DB {
+	return db.reader()
+}
+
+// reader выбирает соединение для чтения
+func (db *Database) reader() *gorm.DB {
+	if len(db.replicas) == 0 {
+		return db.DB
+	}
+	n := atomic.AddUint32(db.next, 1)
+	return db.replicas[int(n)%len(db.replicas)]
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type replicaPost struct {
+	ID    uint `gorm:"primary_key"`
+	Title string
+}
+
+// openTestDB создает базу sqlite в dir с таблицей replicaPost и записями titles
+func openTestDB(t *testing.T, dir, name string, titles ...string) *Database {
+	t.Helper()
+	db, err := NewDatabase(Config{Driver: "sqlite3", Database: filepath.Join(dir, name), LogLevel: "silent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	if err := db.CreateTable(&replicaPost{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range titles {
+		if err := db.Create(&replicaPost{Title: title}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// openReplicated подключает основную базу с одной репликой; обе содержат
+// запись "a", реплика — еще и "replica-only"
+func openReplicated(t *testing.T) (primary, replica *Database) {
+	dir := t.TempDir()
+	primary = openTestDB(t, dir, "primary.db", "a")
+	replica = openTestDB(t, dir, "replica.db", "a", "replica-only")
+	primary.AddReplicas(replica)
+	return primary, replica
+}
+
+func count(t *testing.T, db *Database, title string) int {
+	t.Helper()
+	var n int
+	if err := db.DB.Model(&replicaPost{}).Where("title = ?", title).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestWhereWritesToPrimary(t *testing.T) {
+	primary, replica := openReplicated(t)
+
+	if err := primary.Where("title = ?", "a").Delete(&replicaPost{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, primary, "a"); n != 0 {
+		t.Errorf("Where(...).Delete left %d rows on the primary", n)
+	}
+	if n := count(t, replica, "a"); n != 1 {
+		t.Errorf("Where(...).Delete changed the replica: %d rows left", n)
+	}
+
+	if err := primary.Where("title = ?", "replica-only").Model(&replicaPost{}).Update("title", "b").Error; err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, replica, "replica-only"); n != 1 {
+		t.Error("Where(...).Update changed the replica")
+	}
+}
+
+func TestReadsUseReplicas(t *testing.T) {
+	primary, _ := openReplicated(t)
+
+	var post replicaPost
+	if err := primary.First(&post, "title = ?", "replica-only").Error; err != nil {
+		t.Errorf("First did not read from the replica: %v", err)
+	}
+	var posts []replicaPost
+	if err := primary.Reader().Where("title = ?", "replica-only").Find(&posts).Error; err != nil || len(posts) != 1 {
+		t.Errorf("Reader().Where(...).Find = %d rows, %v; want the replica row", len(posts), err)
+	}
+	if err := primary.Primary().First(&post, "title = ?", "replica-only").Error; err == nil {
+		t.Error("Primary().First read from the replica")
+	}
+}
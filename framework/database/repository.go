@@ -0,0 +1,473 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	defaultPerPage = 25
+	defaultMaxPage = 100
+)
+
+// Operator оператор сравнения в фильтре
+type Operator string
+
+// Поддерживаемые операторы фильтрации
+const (
+	OpEq     Operator = "eq"
+	OpNotEq  Operator = "ne"
+	OpGt     Operator = "gt"
+	OpGte    Operator = "gte"
+	OpLt     Operator = "lt"
+	OpLte    Operator = "lte"
+	OpLike   Operator = "like"
+	OpIn     Operator = "in"
+	OpIsNull Operator = "null"
+)
+
+// Filter условие на одно поле модели
+type Filter struct {
+	Field string
+	Op    Operator
+	Value interface{}
+}
+
+// Eq поле равно значению
+func Eq(field string, value interface{}) Filter { return Filter{field, OpEq, value} }
+
+// NotEq поле не равно значению
+func NotEq(field string, value interface{}) Filter { return Filter{field, OpNotEq, value} }
+
+// Gt поле больше значения
+func Gt(field string, value interface{}) Filter { return Filter{field, OpGt, value} }
+
+// Gte поле больше или равно значению
+func Gte(field string, value interface{}) Filter { return Filter{field, OpGte, value} }
+
+// Lt поле меньше значения
+func Lt(field string, value interface{}) Filter { return Filter{field, OpLt, value} }
+
+// Lte поле меньше или равно значению
+func Lte(field string, value interface{}) Filter { return Filter{field, OpLte, value} }
+
+// Like поле соответствует шаблону LIKE
+func Like(field string, pattern string) Filter { return Filter{field, OpLike, pattern} }
+
+// In поле входит в список значений
+func In(field string, values interface{}) Filter { return Filter{field, OpIn, values} }
+
+// IsNull поле равно NULL (isNull=true) или не равно NULL (isNull=false)
+func IsNull(field string, isNull bool) Filter { return Filter{field, OpIsNull, isNull} }
+
+// Sort порядок сортировки по полю
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort разбирает строку вида "name,-created_at" ("-" — по убыванию)
+func ParseSort(value string) []Sort {
+	var sorts []Sort
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.HasPrefix(part, "-") {
+			sorts = append(sorts, Sort{Field: part[1:], Desc: true})
+		} else {
+			sorts = append(sorts, Sort{Field: strings.TrimPrefix(part, "+")})
+		}
+	}
+	return sorts
+}
+
+// Query параметры выборки списка
+type Query struct {
+	Page    int    // номер страницы для постраничной выборки, с 1
+	PerPage int    // размер страницы
+	Sort    []Sort // пусто — сортировка по умолчанию
+	Filters []Filter
+	After   string // курсор для выборки по ключу (keyset)
+}
+
+// Page страница результатов
+type Page[T any] struct {
+	Items      []T
+	Total      int
+	Page       int
+	PerPage    int
+	TotalPages int
+	NextCursor string // для выборки по ключу; пусто на последней странице
+}
+
+// HasNext сообщает, есть ли следующая страница
+func (p *Page[T]) HasNext() bool {
+	if p.NextCursor != "" {
+		return true
+	}
+	return p.Page > 0 && p.Page < p.TotalPages
+}
+
+// HasPrev сообщает, есть ли предыдущая страница
+func (p *Page[T]) HasPrev() bool {
+	return p.Page > 1
+}
+
+// QueryError ошибка в параметрах выборки: недопустимое поле, оператор или курсор
+type QueryError struct {
+	Field  string
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// RepositoryOptions ограничивает, по каким полям можно сортировать и фильтровать
+type RepositoryOptions struct {
+	SortFields   []string // колонки, допустимые в Query.Sort
+	FilterFields []string // колонки, допустимые в Query.Filters
+	DefaultSort  string   // например "-created_at"; по умолчанию "id"
+	PerPage      int      // размер страницы по умолчанию, 25
+	MaxPerPage   int      // верхняя граница размера страницы, 100
+	PrimaryKey   string   // колонка для однозначного порядка, "id"
+}
+
+// Repository типизированный доступ к таблице модели T
+// с постраничными выборками, сортировкой и фильтрами по разрешенным полям
+type Repository[T any] struct {
+	db      *Database
+	options RepositoryOptions
+	sort    map[string]bool
+	filter  map[string]bool
+}
+
+// NewRepository создает репозиторий для модели T
+func NewRepository[T any](db *Database, options RepositoryOptions) *Repository[T] {
+	if options.PerPage <= 0 {
+		options.PerPage = defaultPerPage
+	}
+	if options.MaxPerPage <= 0 {
+		options.MaxPerPage = defaultMaxPage
+	}
+	if options.PrimaryKey == "" {
+		options.PrimaryKey = "id"
+	}
+	if options.DefaultSort == "" {
+		options.DefaultSort = options.PrimaryKey
+	}
+
+	r := &Repository[T]{
+		db:      db,
+		options: options,
+		sort:    make(map[string]bool),
+		filter:  make(map[string]bool),
+	}
+	for _, field := range options.SortFields {
+		r.sort[field] = true
+	}
+	for _, field := range options.FilterFields {
+		r.filter[field] = true
+	}
+	return r
+}
+
+// Options возвращает настройки репозитория
+func (r *Repository[T]) Options() RepositoryOptions {
+	return r.options
+}
+
+// Find находит запись по первичному ключу
+func (r *Repository[T]) Find(id interface{}) (*T, error) {
+	var item T
+	if err := r.db.reader().First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Count возвращает число записей, подходящих под фильтры
+func (r *Repository[T]) Count(filters ...Filter) (int, error) {
+	return r.count(r.db.reader(), filters)
+}
+
+// count считает записи на соединении reader
+func (r *Repository[T]) count(reader *gorm.DB, filters []Filter) (int, error) {
+	query, err := r.where(reader.Model(new(T)), filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Exists проверяет, есть ли хотя бы одна запись, подходящая под фильтры
+func (r *Repository[T]) Exists(filters ...Filter) (bool, error) {
+	query, err := r.where(r.db.reader().Model(new(T)), filters)
+	if err != nil {
+		return false, err
+	}
+
+	var items []T
+	if err := query.Limit(1).Find(&items).Error; err != nil {
+		return false, err
+	}
+	return len(items) > 0, nil
+}
+
+// List возвращает страницу Query.Page (постраничная выборка через OFFSET)
+func (r *Repository[T]) List(q Query) (*Page[T], error) {
+	sorts, err := r.sorts(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	// Число записей и страница читаются с одной реплики, иначе total
+	// и записи могут относиться к разным моментам репликации
+	reader := r.db.reader()
+	total, err := r.count(reader, q.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	page := q.Page
+	if page < 1 {
+		page = 1
+	}
+	perPage := r.perPage(q.PerPage)
+
+	query, err := r.where(reader, q.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []T
+	err = r.order(query, sorts).Offset((page - 1) * perPage).Limit(perPage).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page[T]{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: (total + perPage - 1) / perPage,
+	}, nil
+}
+
+// ListAfter возвращает записи, следующие за курсором Query.After (keyset).
+// В отличие от OFFSET, скорость не падает на дальних страницах и записи
+// не пропускаются при вставках между запросами.
+func (r *Repository[T]) ListAfter(q Query) (*Page[T], error) {
+	sorts, err := r.sorts(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	reader := r.db.reader()
+	total, err := r.count(reader, q.Filters)
+	if err != nil {
+		return nil, err
+	}
+	perPage := r.perPage(q.PerPage)
+
+	query, err := r.where(reader, q.Filters)
+	if err != nil {
+		return nil, err
+	}
+	if q.After != "" {
+		values, err := r.decodeCursor(q.After, sorts)
+		if err != nil {
+			return nil, err
+		}
+		clause, args := keysetCondition(query, sorts, values)
+		query = query.Where(clause, args...)
+	}
+
+	// Лишняя запись показывает, есть ли следующая страница
+	var items []T
+	if err := r.order(query, sorts).Limit(perPage + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Total: total, PerPage: perPage}
+	if len(items) > perPage {
+		items = items[:perPage]
+		cursor, err := r.encodeCursor(&items[len(items)-1], sorts)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	page.Items = items
+	return page, nil
+}
+
+// perPage ограничивает размер страницы
+func (r *Repository[T]) perPage(requested int) int {
+	if requested <= 0 {
+		return r.options.PerPage
+	}
+	if requested > r.options.MaxPerPage {
+		return r.options.MaxPerPage
+	}
+	return requested
+}
+
+// sorts проверяет поля сортировки и добавляет первичный ключ для однозначного порядка
+func (r *Repository[T]) sorts(requested []Sort) ([]Sort, error) {
+	sorts := requested
+	if len(sorts) == 0 {
+		sorts = ParseSort(r.options.DefaultSort)
+	} else {
+		for _, s := range sorts {
+			if !r.sort[s.Field] {
+				return nil, &QueryError{Field: s.Field, Reason: "sorting by this field is not allowed"}
+			}
+		}
+	}
+
+	for _, s := range sorts {
+		if s.Field == r.options.PrimaryKey {
+			return sorts, nil
+		}
+	}
+	return append(append([]Sort(nil), sorts...), Sort{Field: r.options.PrimaryKey}), nil
+}
+
+// order добавляет ORDER BY
+func (r *Repository[T]) order(query *gorm.DB, sorts []Sort) *gorm.DB {
+	scope := query.NewScope(new(T))
+	for _, s := range sorts {
+		direction := "ASC"
+		if s.Desc {
+			direction = "DESC"
+		}
+		query = query.Order(scope.Quote(s.Field) + " " + direction)
+	}
+	return query
+}
+
+// where добавляет условия фильтров, проверяя поля по списку разрешенных
+func (r *Repository[T]) where(query *gorm.DB, filters []Filter) (*gorm.DB, error) {
+	scope := query.NewScope(new(T))
+	for _, f := range filters {
+		if !r.filter[f.Field] {
+			return nil, &QueryError{Field: f.Field, Reason: "filtering by this field is not allowed"}
+		}
+
+		column := scope.Quote(f.Field)
+		switch f.Op {
+		case OpEq, "":
+			query = query.Where(column+" = ?", f.Value)
+		case OpNotEq:
+			query = query.Where(column+" <> ?", f.Value)
+		case OpGt:
+			query = query.Where(column+" > ?", f.Value)
+		case OpGte:
+			query = query.Where(column+" >= ?", f.Value)
+		case OpLt:
+			query = query.Where(column+" < ?", f.Value)
+		case OpLte:
+			query = query.Where(column+" <= ?", f.Value)
+		case OpLike:
+			query = query.Where(column+" LIKE ?", f.Value)
+		case OpIn:
+			query = query.Where(column+" IN (?)", f.Value)
+		case OpIsNull:
+			if isNull, _ := f.Value.(bool); isNull {
+				query = query.Where(column + " IS NULL")
+			} else {
+				query = query.Where(column + " IS NOT NULL")
+			}
+		default:
+			return nil, &QueryError{Field: f.Field, Reason: fmt.Sprintf("unknown operator %q", f.Op)}
+		}
+	}
+	return query, nil
+}
+
+// keysetCondition строит условие "строка после курсора" для набора сортировок:
+// (a > ?) OR (a = ? AND b > ?) OR ...
+func keysetCondition(query *gorm.DB, sorts []Sort, values []interface{}) (string, []interface{}) {
+	scope := query.NewScope(nil)
+	var clauses []string
+	var args []interface{}
+	for i, s := range sorts {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, scope.Quote(sorts[j].Field)+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if s.Desc {
+			op = " < ?"
+		}
+		parts = append(parts, scope.Quote(s.Field)+op)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// encodeCursor сохраняет значения полей сортировки последней записи
+func (r *Repository[T]) encodeCursor(item *T, sorts []Sort) (string, error) {
+	scope := r.db.NewScope(item)
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		field, ok := scope.FieldByName(s.Field)
+		if !ok {
+			return "", &QueryError{Field: s.Field, Reason: "unknown field"}
+		}
+		values[i] = field.Field.Interface()
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor восстанавливает значения курсора в типах полей модели,
+// чтобы сравнение в SQL шло с теми же типами (например, time.Time)
+func (r *Repository[T]) decodeCursor(cursor string, sorts []Sort) ([]interface{}, error) {
+	invalid := &QueryError{Field: "cursor", Reason: "invalid cursor"}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) != len(sorts) {
+		return nil, invalid
+	}
+
+	scope := r.db.NewScope(new(T))
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		field, ok := scope.FieldByName(s.Field)
+		if !ok {
+			return nil, &QueryError{Field: s.Field, Reason: "unknown field"}
+		}
+		value := reflect.New(field.Struct.Type)
+		if err := json.Unmarshal(raw[i], value.Interface()); err != nil {
+			return nil, invalid
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
@@ -0,0 +1,33 @@
+package database
+
+import "testing"
+
+// TestListReadsOneReplica проверяет, что total и записи страницы берутся
+// с одной реплики, даже если реплики отстают по-разному
+func TestListReadsOneReplica(t *testing.T) {
+	dir := t.TempDir()
+	primary := openTestDB(t, dir, "primary.db", "a", "b", "c")
+	primary.AddReplicas(
+		openTestDB(t, dir, "replica1.db", "a"),
+		openTestDB(t, dir, "replica2.db", "a", "b", "c"),
+	)
+	repo := NewRepository[replicaPost](primary, RepositoryOptions{})
+
+	for i := 0; i < 4; i++ {
+		page, err := repo.List(Query{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != len(page.Items) {
+			t.Errorf("List: total %d, items %d", page.Total, len(page.Items))
+		}
+
+		page, err = repo.ListAfter(Query{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != len(page.Items) {
+			t.Errorf("ListAfter: total %d, items %d", page.Total, len(page.Items))
+		}
+	}
+}
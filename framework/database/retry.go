@@ -0,0 +1,63 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	defaultRetryInterval    = time.Second
+	defaultMaxRetryInterval = 30 * time.Second
+)
+
+// RetryConfig задает экспоненциальные повторы подключения к базе,
+// например когда контейнер приложения стартует раньше базы данных
+type RetryConfig struct {
+	Attempts        int           // число повторов после первой неудачи; 0 — без повторов
+	InitialInterval time.Duration // пауза перед первым повтором, по умолчанию 1s
+	MaxInterval     time.Duration // верхняя граница паузы, по умолчанию 30s
+}
+
+// withRetry выполняет fn, повторяя её с удваивающейся паузой
+func withRetry(config RetryConfig, fn func() error) error {
+	interval := config.InitialInterval
+	if interval <= 0 {
+		interval = defaultRetryInterval
+	}
+	maxInterval := config.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxRetryInterval
+	}
+
+	err := fn()
+	for attempt := 1; err != nil && attempt <= config.Attempts; attempt++ {
+		log.Printf("Database is not available (%v), retry %d/%d in %s", err, attempt, config.Attempts, interval)
+		time.Sleep(interval)
+
+		err = fn()
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+	return err
+}
+
+// Ping проверяет доступность основной базы, реплик и именованных баз
+func (db *Database) Ping() error {
+	if err := db.DB.DB().Ping(); err != nil {
+		return err
+	}
+	for _, replica := range db.replicas {
+		if err := replica.DB().Ping(); err != nil {
+			return err
+		}
+	}
+	for name, named := range db.named {
+		if err := named.Ping(); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return nil
+}
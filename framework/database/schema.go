@@ -0,0 +1,321 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// schemaSeparator разделяет операторы в файле схемы
+const schemaSeparator = ";\n\n"
+
+var autoIncrementOption = regexp.MustCompile(` AUTO_INCREMENT=\d+`)
+
+// DumpSchema возвращает SQL, воссоздающий текущую схему базы данных,
+// включая записи schema_migrations
+func (db *Database) DumpSchema() (string, error) {
+	var statements []string
+	var err error
+
+	dialect := db.Dialect().GetName()
+	switch dialect {
+	case "sqlite3":
+		statements, err = db.dumpSQLite()
+	case "mysql":
+		statements, err = db.dumpMySQL()
+	case "postgres":
+		statements, err = db.dumpPostgres()
+	default:
+		return "", fmt.Errorf("schema dump is not supported for %s", dialect)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to dump schema: %v", err)
+	}
+
+	versions, err := db.dumpSchemaMigrations()
+	if err != nil {
+		return "", fmt.Errorf("failed to dump schema_migrations: %v", err)
+	}
+	statements = append(statements, versions...)
+
+	var b strings.Builder
+	b.WriteString("-- This file is auto-generated by gorails db schema dump.\n")
+	b.WriteString("-- Edit migrations instead and regenerate it.\n")
+	b.WriteString("-- dialect: " + dialect + "\n\n")
+	for _, stmt := range statements {
+		b.WriteString(strings.TrimSpace(stmt))
+		b.WriteString(schemaSeparator)
+	}
+	return b.String(), nil
+}
+
+// LoadSchema выполняет SQL, полученный из DumpSchema.
+// База данных должна быть пустой.
+func (db *Database) LoadSchema(schema string) error {
+	var statements []string
+	for _, chunk := range strings.Split(schema, schemaSeparator) {
+		var lines []string
+		for _, line := range strings.Split(chunk, "\n") {
+			if dialect := strings.TrimPrefix(line, "-- dialect: "); dialect != line {
+				if current := db.Dialect().GetName(); dialect != current {
+					return fmt.Errorf("schema was dumped from %s, cannot load into %s", dialect, current)
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "--") {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		if stmt := strings.TrimSpace(strings.Join(lines, "\n")); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	// В MySQL DDL не откатывается, но транзакция закрепляет одно соединение,
+	// и SET FOREIGN_KEY_CHECKS из схемы действует на все её операторы
+	return db.Transaction(func(tx *Database) error {
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to load schema: %v\n%s", err, stmt)
+			}
+		}
+		return nil
+	})
+}
+
+// dumpSQLite читает определения объектов из sqlite_master
+func (db *Database) dumpSQLite() ([]string, error) {
+	rows, err := db.Raw(`SELECT sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, name`).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+	}
+	return statements, rows.Err()
+}
+
+// Tables возвращает имена таблиц текущей базы
+func (db *Database) Tables() ([]string, error) {
+	switch db.Dialect().GetName() {
+	case "sqlite3":
+		return db.stringColumn(`SELECT name FROM sqlite_master
+			WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	case "mysql":
+		return db.stringColumn("SHOW FULL TABLES WHERE Table_type = 'BASE TABLE'")
+	case "postgres":
+		return db.stringColumn(`SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+			ORDER BY table_name`)
+	default:
+		return nil, fmt.Errorf("listing tables is not supported for %s", db.Dialect().GetName())
+	}
+}
+
+// dumpMySQL использует SHOW CREATE TABLE для каждой таблицы. Таблицы идут
+// по алфавиту с внешними ключами внутри CREATE TABLE, поэтому проверка ключей
+// на время их создания отключается.
+func (db *Database) dumpMySQL() ([]string, error) {
+	tables, err := db.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{"SET FOREIGN_KEY_CHECKS=0"}
+	for _, table := range tables {
+		var name, stmt string
+		if err := db.Raw("SHOW CREATE TABLE `"+table+"`").Row().Scan(&name, &stmt); err != nil {
+			return nil, err
+		}
+		statements = append(statements, autoIncrementOption.ReplaceAllString(stmt, ""))
+	}
+	return append(statements, "SET FOREIGN_KEY_CHECKS=1"), nil
+}
+
+// dumpPostgres собирает CREATE TABLE из information_schema, индексы из pg_indexes
+func (db *Database) dumpPostgres() ([]string, error) {
+	tables, err := db.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, table := range tables {
+		stmt, err := db.postgresCreateTable(table)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+	}
+
+	// Внешние ключи добавляются после создания всех таблиц
+	foreignKeys, err := db.stringColumn(`SELECT format('ALTER TABLE %I ADD CONSTRAINT %I %s',
+			t.relname, c.conname, pg_get_constraintdef(c.oid))
+		FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = current_schema() AND c.contype = 'f'
+		ORDER BY t.relname, c.conname`)
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, foreignKeys...)
+
+	indexes, err := db.stringColumn(`SELECT i.indexdef FROM pg_indexes i
+		WHERE i.schemaname = current_schema() AND NOT EXISTS (
+			SELECT 1 FROM pg_constraint c
+			WHERE c.conname = i.indexname AND c.contype IN ('p', 'u'))
+		ORDER BY i.tablename, i.indexname`)
+	if err != nil {
+		return nil, err
+	}
+	return append(statements, indexes...), nil
+}
+
+// postgresCreateTable строит CREATE TABLE для одной таблицы PostgreSQL
+func (db *Database) postgresCreateTable(table string) (string, error) {
+	rows, err := db.Raw(`SELECT column_name, data_type, udt_name, character_maximum_length,
+			numeric_precision, numeric_scale, is_nullable, column_default
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ?
+		ORDER BY ordinal_position`, table).Rows()
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name, dataType, udtName, nullable string
+		var length, precision, scale *int
+		var def *string
+		if err := rows.Scan(&name, &dataType, &udtName, &length, &precision, &scale, &nullable, &def); err != nil {
+			return "", err
+		}
+
+		colType := postgresColumnType(dataType, udtName, length, precision, scale)
+		column := fmt.Sprintf("  %q %s", name, colType)
+		if def != nil && strings.HasPrefix(*def, "nextval(") {
+			// Последовательность создается вместе с serial-колонкой
+			if colType == "bigint" {
+				column = fmt.Sprintf("  %q bigserial", name)
+			} else {
+				column = fmt.Sprintf("  %q serial", name)
+			}
+		} else if def != nil {
+			column += " DEFAULT " + *def
+		}
+		if nullable == "NO" {
+			column += " NOT NULL"
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	constraints, err := db.stringColumn(`SELECT pg_get_constraintdef(c.oid) FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = current_schema() AND t.relname = ? AND c.contype IN ('p', 'u', 'c')
+		ORDER BY c.contype DESC, c.conname`, table)
+	if err != nil {
+		return "", err
+	}
+	for _, constraint := range constraints {
+		columns = append(columns, "  "+constraint)
+	}
+
+	return fmt.Sprintf("CREATE TABLE %q (\n%s\n)", table, strings.Join(columns, ",\n")), nil
+}
+
+// postgresColumnType переводит описание из information_schema в тип колонки
+func postgresColumnType(dataType, udtName string, length, precision, scale *int) string {
+	switch dataType {
+	case "character varying":
+		if length != nil {
+			return fmt.Sprintf("varchar(%d)", *length)
+		}
+		return "varchar"
+	case "character":
+		if length != nil {
+			return fmt.Sprintf("char(%d)", *length)
+		}
+		return "char"
+	case "numeric":
+		if precision != nil && scale != nil {
+			return fmt.Sprintf("numeric(%d,%d)", *precision, *scale)
+		}
+		return "numeric"
+	case "ARRAY":
+		return strings.TrimPrefix(udtName, "_") + "[]"
+	case "USER-DEFINED":
+		return udtName
+	default:
+		return dataType
+	}
+}
+
+// dumpSchemaMigrations возвращает INSERT для каждой примененной версии
+func (db *Database) dumpSchemaMigrations() ([]string, error) {
+	if !db.HasTable(&SchemaMigration{}) {
+		return nil, nil
+	}
+
+	var rows []SchemaMigration
+	if err := db.DB.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, row := range rows {
+		statements = append(statements, fmt.Sprintf(
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES (%s, %s, %s)",
+			quoteSQL(row.Version), quoteSQL(row.Name), quoteSQL(row.AppliedAt.UTC().Format("2006-01-02 15:04:05"))))
+	}
+	return statements, nil
+}
+
+// stringColumn выполняет запрос и возвращает первую колонку результата
+func (db *Database) stringColumn(query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for rows.Next() {
+		// Остальные колонки (например, Table_type в SHOW FULL TABLES) игнорируются
+		var value string
+		dest := []interface{}{&value}
+		for i := 1; i < len(columns); i++ {
+			dest = append(dest, new(interface{}))
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// quoteSQL экранирует строковый литерал
+func quoteSQL(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
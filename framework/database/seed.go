@@ -0,0 +1,166 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SeedFunc заполняет базу данных данными из кода
+type SeedFunc func(db *Database) error
+
+// seedModel модель, которую можно заполнить из файлов db/seeds/<name>.yml|json
+type seedModel struct {
+	name string
+	typ  reflect.Type
+	keys []string
+}
+
+// seedFunc функция заполнения, ограниченная окружениями
+type seedFunc struct {
+	fn   SeedFunc
+	envs []string
+}
+
+var (
+	seedMu     sync.Mutex
+	seedModels []seedModel
+	seedFuncs  []seedFunc
+)
+
+// RegisterSeedModel связывает файлы db/seeds/<name>.{yml,yaml,json} с моделью.
+// naturalKey — колонки, по которым запись ищется перед обновлением,
+// благодаря чему повторный запуск db seed не создает дубликатов.
+// Файлы загружаются в порядке регистрации моделей.
+func RegisterSeedModel(name string, model interface{}, naturalKey ...string) {
+	seedMu.Lock()
+	defer seedMu.Unlock()
+
+	if len(naturalKey) == 0 {
+		panic(fmt.Sprintf("database: seed model %s has no natural key", name))
+	}
+	typ := reflect.TypeOf(model)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	seedModels = append(seedModels, seedModel{name: name, typ: typ, keys: naturalKey})
+}
+
+// RegisterSeed регистрирует функцию заполнения из db/seeds.go.
+// Если окружения не указаны, функция выполняется во всех окружениях.
+func RegisterSeed(fn SeedFunc, envs ...string) {
+	seedMu.Lock()
+	defer seedMu.Unlock()
+
+	seedFuncs = append(seedFuncs, seedFunc{fn: fn, envs: envs})
+}
+
+// Seeder загружает файлы из db/seeds и выполняет зарегистрированные функции
+type Seeder struct {
+	db  *Database
+	dir string
+	env string
+}
+
+// NewSeeder создает загрузчик начальных данных для окружения env
+func NewSeeder(db *Database, dir, env string) *Seeder {
+	return &Seeder{db: db, dir: dir, env: env}
+}
+
+// Run загружает общие файлы из dir, затем файлы из dir/<env>,
+// затем выполняет функции из db/seeds.go. Всё выполняется в одной транзакции.
+func (s *Seeder) Run() error {
+	seedMu.Lock()
+	models := append([]seedModel(nil), seedModels...)
+	funcs := append([]seedFunc(nil), seedFuncs...)
+	seedMu.Unlock()
+
+	return s.db.Transaction(func(tx *Database) error {
+		return s.run(tx, models, funcs)
+	})
+}
+
+func (s *Seeder) run(db *Database, models []seedModel, funcs []seedFunc) error {
+	for _, dir := range []string{s.dir, filepath.Join(s.dir, s.env)} {
+		for _, model := range models {
+			records, path, err := readSeedFile(dir, model.name)
+			if err != nil {
+				return err
+			}
+			for i, record := range records {
+				if err := upsert(db, model, record); err != nil {
+					return fmt.Errorf("%s: record %d: %v", path, i+1, err)
+				}
+			}
+		}
+	}
+
+	for _, f := range funcs {
+		if !f.appliesTo(s.env) {
+			continue
+		}
+		if err := f.fn(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appliesTo проверяет, нужно ли выполнять функцию в окружении env
+func (f seedFunc) appliesTo(env string) bool {
+	if len(f.envs) == 0 {
+		return true
+	}
+	for _, e := range f.envs {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
+
+// upsert находит запись по естественному ключу и обновляет её или создает новую
+func upsert(db *Database, model seedModel, record map[string]interface{}) error {
+	where := make(map[string]interface{}, len(model.keys))
+	for _, key := range model.keys {
+		value, ok := record[key]
+		if !ok {
+			return fmt.Errorf("natural key %q is missing", key)
+		}
+		where[key] = value
+	}
+
+	instance := reflect.New(model.typ).Interface()
+	return db.DB.Where(where).Assign(record).FirstOrCreate(instance).Error
+}
+
+// readSeedFile читает список записей из <dir>/<name>.yml, .yaml или .json
+func readSeedFile(dir, name string) ([]map[string]interface{}, string, error) {
+	for _, ext := range []string{".yml", ".yaml", ".json"} {
+		path := filepath.Join(dir, name+ext)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, path, err
+		}
+
+		var records []map[string]interface{}
+		if ext == ".json" {
+			err = json.Unmarshal(data, &records)
+		} else {
+			err = yaml.Unmarshal(data, &records)
+		}
+		if err != nil {
+			return nil, path, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		return records, path, nil
+	}
+	return nil, "", nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// deletedAtColumn колонка модели с полем DeletedAt (см. models.SoftDeletable).
+// GORM сам исключает такие записи из выборок, а Delete лишь заполняет колонку.
+const deletedAtColumn = "deleted_at"
+
+// WithDeleted возвращает копию базы, запросы которой видят и мягко удаленные записи.
+// Delete через такую копию удаляет записи безвозвратно.
+func (db *Database) WithDeleted() *Database {
+	return db.scoped(func(g *gorm.DB) *gorm.DB {
+		return g.Unscoped()
+	})
+}
+
+// OnlyDeleted возвращает копию базы, запросы которой видят только мягко удаленные записи
+func (db *Database) OnlyDeleted() *Database {
+	return db.scoped(func(g *gorm.DB) *gorm.DB {
+		return g.Unscoped().Where(deletedAtColumn + " IS NOT NULL")
+	})
+}
+
+// Restore снимает пометку об удалении с записи value, найденной по первичному ключу
+func (db *Database) Restore(value interface{}) error {
+	scope := db.NewScope(value)
+	if _, ok := scope.FieldByName("DeletedAt"); !ok {
+		return fmt.Errorf("%s does not support soft delete", scope.TableName())
+	}
+	if scope.PrimaryKeyZero() {
+		return fmt.Errorf("cannot restore %s record without primary key", scope.TableName())
+	}
+	return db.DB.Unscoped().Model(value).UpdateColumn(deletedAtColumn, nil).Error
+}
+
+// Purge безвозвратно удаляет записи, в том числе мягко удаленные
+func (db *Database) Purge(value interface{}, where ...interface{}) *gorm.DB {
+	return db.DB.Unscoped().Delete(value, where...)
+}
+
+// SoftDeleteTables возвращает таблицы с колонкой deleted_at
+func (db *Database) SoftDeleteTables() ([]string, error) {
+	tables, err := db.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, table := range tables {
+		if db.Dialect().HasColumn(table, deletedAtColumn) {
+			result = append(result, table)
+		}
+	}
+	return result, nil
+}
+
+// PurgeDeleted безвозвратно удаляет из table записи, мягко удаленные раньше before,
+// и возвращает их количество
+func (db *Database) PurgeDeleted(table string, before time.Time) (int64, error) {
+	if !db.Dialect().HasColumn(table, deletedAtColumn) {
+		return 0, fmt.Errorf("table %s has no %s column", table, deletedAtColumn)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s IS NOT NULL AND %s < ?",
+		db.Dialect().Quote(table), deletedAtColumn, deletedAtColumn)
+	result := db.DB.Exec(query, before)
+	return result.RowsAffected, result.Error
+}
+
+// scoped возвращает копию базы, в которой fn применена к основному соединению и репликам
+func (db *Database) scoped(fn func(*gorm.DB) *gorm.DB) *Database {
+	scoped := *db
+	scoped.DB = fn(db.DB)
+	scoped.replicas = make([]*gorm.DB, len(db.replicas))
+	for i, replica := range db.replicas {
+		scoped.replicas[i] = fn(replica)
+	}
+	return &scoped
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// txStateKey ключ настройки GORM, под которым транзакция хранит своё состояние,
+// чтобы оно было доступно и в обработчиках GORM (см. FromScope)
+const txStateKey = "gorails:transaction"
+
+// txState глубина вложенности и обработчики, отложенные до конца транзакции
+type txState struct {
+	depth    int
+	commit   []func()
+	rollback []func()
+}
+
+// Transaction выполняет fn в транзакции: фиксирует её, если fn вернула nil,
+// и откатывает при ошибке или панике (панику пробрасывает дальше).
+// Вызов внутри другой транзакции создает SAVEPOINT, поэтому ошибка во
+// вложенном блоке откатывает только его изменения.
+func (db *Database) Transaction(fn func(tx *Database) error) (err error) {
+	if db.txDepth > 0 {
+		return db.savepoint(fn)
+	}
+
+	gormTx := db.DB.Begin()
+	if gormTx.Error != nil {
+		return gormTx.Error
+	}
+	state := &txState{depth: 1}
+	tx := &Database{DB: gormTx.Set(txStateKey, state), named: db.named, txDepth: 1}
+
+	defer func() {
+		if r := recover(); r != nil {
+			gormTx.Rollback()
+			state.runRollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		gormTx.Rollback()
+		state.runRollback()
+		return err
+	}
+	if err := gormTx.Commit().Error; err != nil {
+		state.runRollback()
+		return err
+	}
+	state.runCommit()
+	return nil
+}
+
+// InTransaction сообщает, выполняется ли работа внутри транзакции
+func (db *Database) InTransaction() bool {
+	return db.txDepth > 0
+}
+
+// AfterCommit откладывает fn до фиксации внешней транзакции.
+// Вне транзакции, начатой Transaction, fn выполняется сразу.
+func (db *Database) AfterCommit(fn func()) {
+	if state := db.txState(); state != nil {
+		state.commit = append(state.commit, fn)
+		return
+	}
+	fn()
+}
+
+// AfterRollback откладывает fn до отката транзакции (или её SAVEPOINT).
+// Вне транзакции, начатой Transaction, fn не выполняется.
+func (db *Database) AfterRollback(fn func()) {
+	if state := db.txState(); state != nil {
+		state.rollback = append(state.rollback, fn)
+	}
+}
+
+// FromScope возвращает Database для кода внутри обработчиков GORM:
+// запросы выполняются в той же транзакции, что и текущая операция
+func FromScope(scope *gorm.Scope) *Database {
+	db := &Database{DB: scope.NewDB()}
+	if value, ok := scope.Get(txStateKey); ok {
+		db.txDepth = value.(*txState).depth
+	} else if _, ok := scope.DB().CommonDB().(*sql.Tx); ok {
+		// Неявная транзакция, которую GORM открывает на время Create, Save и Delete
+		db.txDepth = 1
+	}
+	return db
+}
+
+// txState возвращает состояние транзакции, начатой Transaction
+func (db *Database) txState() *txState {
+	if value, ok := db.DB.Get(txStateKey); ok {
+		return value.(*txState)
+	}
+	return nil
+}
+
+// savepoint выполняет fn во вложенной транзакции
+func (db *Database) savepoint(fn func(tx *Database) error) error {
+	name := fmt.Sprintf("gorails_sp_%d", db.txDepth)
+	if err := db.Exec("SAVEPOINT " + name).Error; err != nil {
+		return err
+	}
+	state := &txState{depth: db.txDepth + 1}
+	nested := &Database{DB: db.DB.Set(txStateKey, state), named: db.named, txDepth: db.txDepth + 1}
+
+	defer func() {
+		if r := recover(); r != nil {
+			db.Exec("ROLLBACK TO SAVEPOINT " + name)
+			state.runRollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(nested); err != nil {
+		if rbErr := db.Exec("ROLLBACK TO SAVEPOINT " + name).Error; rbErr != nil {
+			return fmt.Errorf("%v (rollback to savepoint failed: %v)", err, rbErr)
+		}
+		state.runRollback()
+		return err
+	}
+	if err := db.Exec("RELEASE SAVEPOINT " + name).Error; err != nil {
+		return err
+	}
+
+	// Изменения SAVEPOINT окончательно сохранятся или откатятся вместе с внешней транзакцией
+	if parent := db.txState(); parent != nil {
+		parent.commit = append(parent.commit, state.commit...)
+		parent.rollback = append(parent.rollback, state.rollback...)
+	}
+	return nil
+}
+
+func (s *txState) runCommit() {
+	for _, fn := range s.commit {
+		fn()
+	}
+}
+
+func (s *txState) runRollback() {
+	for _, fn := range s.rollback {
+		fn()
+	}
+}
+
+// IsUniqueViolation проверяет, что ошибка вызвана нарушением уникального индекса
+func IsUniqueViolation(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case sqlite3.Error:
+		return e.ExtendedCode == sqlite3.ErrConstraintUnique || e.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	case *mysql.MySQLError:
+		return e.Number == 1062
+	case *pq.Error:
+		return e.Code == "23505"
+	}
+	// GORM может обернуть ошибку драйвера в строку
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") ||
+		strings.Contains(msg, "Duplicate entry") ||
+		strings.Contains(msg, "duplicate key value violates unique constraint")
+}
@@ -0,0 +1,204 @@
+package generators
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Действия с файлами, о которых сообщают генераторы
+const (
+	ActionCreate    = "create"    // файла не было
+	ActionIdentical = "identical" // файл уже совпадает со сгенерированным
+	ActionConflict  = "conflict"  // файл отличается, а --force и --skip не заданы
+	ActionForce     = "force"     // отличающийся файл перезаписан (--force)
+	ActionSkip      = "skip"      // отличающийся файл оставлен как есть (--skip)
+	ActionUpdate    = "update"    // в существующий файл внесена правка, например маршрут
+	ActionRun       = "run"       // выполнена команда, например go mod tidy
+	ActionRemove    = "remove"    // файл удален командой destroy
+)
+
+// Options управляет записью файлов генераторами
+type Options struct {
+	Force   bool      // перезаписывать файлы, отличающиеся от сгенерированных
+	Skip    bool      // оставлять такие файлы без изменений
+	Pretend bool      // только вывести действия, ничего не записывая
+	Out     io.Writer // отчет о действиях; по умолчанию os.Stdout
+}
+
+// ConflictError сообщает, что файлы уже существуют с другим содержимым.
+// В этом случае генератор не записывает ни одного файла.
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("files already exist with different content (use --force to overwrite or --skip to keep them): %s",
+		strings.Join(e.Paths, ", "))
+}
+
+// fileSet файлы, которые генератор собирается записать. Сначала для каждого
+// определяется действие, и только если конфликтов нет, файлы записываются.
+type fileSet struct {
+	root    string // папка, относительно которой заданы пути, "" — текущая
+	options Options
+	entries []fileEntry
+}
+
+type fileEntry struct {
+	path    string
+	content string
+	update  bool // правка существующего файла: отличие от него не конфликт
+}
+
+func newFileSet(root string, options Options) *fileSet {
+	if options.Out == nil {
+		options.Out = os.Stdout
+	}
+	return &fileSet{root: root, options: options}
+}
+
+// add добавляет сгенерированный файл
+func (s *fileSet) add(path, content string) {
+	s.entries = append(s.entries, fileEntry{path: path, content: content})
+}
+
+// update добавляет новое содержимое существующего файла
+func (s *fileSet) update(path, content string) {
+	s.entries = append(s.entries, fileEntry{path: path, content: content, update: true})
+}
+
+// run определяет действия, выводит отчет и записывает файлы
+func (s *fileSet) run() error {
+	if s.options.Force && s.options.Skip {
+		return fmt.Errorf("--force and --skip cannot be used together")
+	}
+
+	actions := make([]string, len(s.entries))
+	var conflicts []string
+	for i, entry := range s.entries {
+		action, err := s.action(entry)
+		if err != nil {
+			return err
+		}
+		actions[i] = action
+		if action == ActionConflict {
+			conflicts = append(conflicts, entry.path)
+		}
+	}
+
+	for i, entry := range s.entries {
+		s.say(actions[i], entry.path)
+		if actions[i] == ActionConflict {
+			s.printDiff(entry)
+		}
+	}
+	if len(conflicts) > 0 && !s.options.Pretend {
+		return &ConflictError{Paths: conflicts}
+	}
+	if s.options.Pretend {
+		return nil
+	}
+
+	manifest, err := readManifest(s.root)
+	if err != nil {
+		return err
+	}
+	for i, entry := range s.entries {
+		if actions[i] == ActionSkip {
+			continue
+		}
+		if actions[i] != ActionIdentical {
+			full := filepath.Join(s.root, entry.path)
+			if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(full, []byte(entry.content), 0644); err != nil {
+				return err
+			}
+		}
+		// Правки общих файлов (маршрутов) не отслеживаются: destroy их не удаляет
+		if !entry.update {
+			manifest[filepath.ToSlash(entry.path)] = checksum([]byte(entry.content))
+		}
+	}
+	return writeManifest(s.root, manifest)
+}
+
+// say выводит строку отчета: действие и файл или команду
+func (s *fileSet) say(action, subject string) {
+	fmt.Fprintf(s.options.Out, "%12s  %s\n", action, subject)
+}
+
+// command выполняет команду в папке root с дополнительными переменными
+// окружения env; при Pretend только сообщает о ней
+func (s *fileSet) command(env []string, name string, args ...string) error {
+	line := strings.Join(append([]string{filepath.Base(name)}, args...), " ")
+	s.say(ActionRun, line)
+	if s.options.Pretend {
+		return nil
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = s.root
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = s.options.Out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %v", line, err)
+	}
+	return nil
+}
+
+// action определяет, что произойдет с файлом
+func (s *fileSet) action(entry fileEntry) (string, error) {
+	existing, err := os.ReadFile(filepath.Join(s.root, entry.path))
+	switch {
+	case os.IsNotExist(err):
+		return ActionCreate, nil
+	case err != nil:
+		return "", err
+	case string(existing) == entry.content:
+		return ActionIdentical, nil
+	case entry.update:
+		return ActionUpdate, nil
+	case s.options.Force:
+		return ActionForce, nil
+	case s.options.Skip:
+		return ActionSkip, nil
+	}
+	return ActionConflict, nil
+}
+
+// printDiff выводит отличия существующего файла от сгенерированного
+func (s *fileSet) printDiff(entry fileEntry) {
+	existing, err := os.ReadFile(filepath.Join(s.root, entry.path))
+	if err != nil {
+		return
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        lines(string(existing)),
+		B:        lines(entry.content),
+		FromFile: entry.path,
+		ToFile:   entry.path + " (generated)",
+		Context:  3,
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprint(s.options.Out, diff)
+}
+
+// lines разбивает текст на строки, сохраняя переводы строк
+func lines(text string) []string {
+	result := strings.SplitAfter(text, "\n")
+	if result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+	return result
+}
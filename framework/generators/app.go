@@ -0,0 +1,217 @@
+package generators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// appFiles файлы нового приложения и шаблоны, из которых они создаются
+var appFiles = map[string]string{
+	"go.mod":                 "app/go.mod.tmpl",
+	"main.go":                "app/main.go.tmpl",
+	"config/config.yaml":     "app/config.yaml.tmpl",
+	"config/inflections.yml": "app/inflections.yml.tmpl",
+	"routes/routes.go":       "app/routes.go.tmpl",
+	"README.md":              "app/README.md.tmpl",
+	".gitignore":             "app/gitignore.tmpl",
+	"app/controllers/application_controller.go": "app/application_controller.go.tmpl",
+	"db/migrate/migrate.go":                     "app/migrate.go.tmpl",
+	"db/seeds.go":                               "app/seeds.go.tmpl",
+}
+
+// minimalSkipped файлы, которых нет в приложении --minimal
+var minimalSkipped = map[string]bool{
+	"config/inflections.yml": true,
+	"db/seeds.go":            true,
+}
+
+// appDatabases драйверы, для которых new пишет секцию database в config.yaml
+var appDatabases = []string{"sqlite3", "mysql", "postgres"}
+
+// appData данные шаблонов нового приложения
+type appData struct {
+	Name          string // имя модуля приложения
+	FrameworkPath string // папка фреймворка для replace в go.mod или пусто
+	Database      string // sqlite3, mysql или postgres
+	API           bool   // только API: без app/views и public/assets
+	Auth          bool   // маршруты пользователей и аутентификации, миграция users
+	Seeds         bool   // есть db/seeds.go
+}
+
+// frameworkModule модуль фреймворка, от которого зависит приложение
+const frameworkModule = "go-rails"
+
+// NewAppOptions настройки gorails new
+type NewAppOptions struct {
+	// FrameworkPath папка с исходниками фреймворка: в go.mod приложения
+	// добавляется replace на неё. Если не задана, берется GORAILS_FRAMEWORK_PATH,
+	// а при запуске из исходников фреймворка — текущая папка.
+	FrameworkPath string
+	// SkipTidy не запускает go mod tidy в созданном приложении
+	SkipTidy bool
+	// Database драйвер базы данных: sqlite3 (по умолчанию), mysql или postgres
+	Database string
+	// API создает приложение только для API, без представлений и статических файлов
+	API bool
+	// SkipAuth не подключает пользователей и аутентификацию фреймворка
+	SkipAuth bool
+	// Minimal включает API и SkipAuth и не создает inflections.yml и seeds.go
+	Minimal bool
+	// Template YAML- или Go-шаблон, применяемый к созданному приложению
+	Template string
+}
+
+// CreateNewApp создает новое приложение в папке appName
+func CreateNewApp(appName string, appOptions NewAppOptions, options Options) error {
+	data, err := newAppData(appName, appOptions)
+	if err != nil {
+		return err
+	}
+	var tmpl *appTemplate
+	if appOptions.Template != "" {
+		if tmpl, err = loadAppTemplate(appOptions.Template); err != nil {
+			return err
+		}
+	}
+
+	paths := make([]string, 0, len(appFiles))
+	for path := range appFiles {
+		if appOptions.Minimal && minimalSkipped[path] {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	files := newFileSet(appName, options)
+	for _, path := range paths {
+		content, err := render(appFiles[path], data)
+		if err != nil {
+			return err
+		}
+		files.add(filepath.FromSlash(path), content)
+	}
+	if data.Auth {
+		path, version, err := migrationFile(appName, "create_users")
+		if err != nil {
+			return err
+		}
+		content, err := render("app/create_users.go.tmpl", migrationData{Name: "create_users", Version: version})
+		if err != nil {
+			return err
+		}
+		files.add(path, content)
+	}
+	if tmpl != nil {
+		tmpl.addFiles(files)
+	}
+	if err := files.run(); err != nil {
+		return err
+	}
+
+	if !options.Pretend {
+		for _, dir := range appDirs(appName, data) {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !appOptions.SkipTidy {
+		if err := tidy(files, data); err != nil {
+			return err
+		}
+	}
+	if tmpl == nil {
+		return nil
+	}
+	if err := tmpl.apply(files); err != nil {
+		return err
+	}
+	if !appOptions.SkipTidy {
+		return tidy(files, data)
+	}
+	return nil
+}
+
+// newAppData проверяет настройки и собирает данные шаблонов приложения
+func newAppData(appName string, appOptions NewAppOptions) (appData, error) {
+	root, err := filepath.Abs(appName)
+	if err != nil {
+		return appData{}, err
+	}
+	frameworkPath, err := resolveFrameworkPath(appOptions.FrameworkPath)
+	if err != nil {
+		return appData{}, err
+	}
+
+	database := appOptions.Database
+	if database == "" {
+		database = appDatabases[0]
+	}
+	supported := false
+	for _, name := range appDatabases {
+		supported = supported || name == database
+	}
+	if !supported {
+		return appData{}, fmt.Errorf("unsupported database %q (use %s)", database, strings.Join(appDatabases, ", "))
+	}
+
+	return appData{
+		Name:          filepath.Base(root),
+		FrameworkPath: frameworkPath,
+		Database:      database,
+		API:           appOptions.API || appOptions.Minimal,
+		Auth:          !appOptions.SkipAuth && !appOptions.Minimal,
+		Seeds:         !appOptions.Minimal,
+	}, nil
+}
+
+// appDirs папки, в которые генератор не кладет файлов
+func appDirs(appName string, data appData) []string {
+	dirs := []string{filepath.Join(appName, "app", "models")}
+	if !data.API {
+		dirs = append(dirs,
+			filepath.Join(appName, "app", "views"),
+			filepath.Join(appName, "public", "assets"))
+	}
+	if data.Seeds {
+		dirs = append(dirs, filepath.Join(appName, "db", "seeds"))
+	}
+	return dirs
+}
+
+// tidy выполняет go mod tidy в приложении
+func tidy(files *fileSet, data appData) error {
+	err := files.command(nil, "go", "mod", "tidy")
+	if err != nil && data.FrameworkPath == "" {
+		return fmt.Errorf("%v (%s is not published, pass --framework-path with its sources)", err, frameworkModule)
+	}
+	return err
+}
+
+// resolveFrameworkPath возвращает абсолютный путь к исходникам фреймворка
+// для replace в go.mod или пустую строку, если он неизвестен
+func resolveFrameworkPath(path string) (string, error) {
+	if path == "" {
+		path = os.Getenv("GORAILS_FRAMEWORK_PATH")
+	}
+	if path == "" {
+		if moduleName("go.mod") != frameworkModule {
+			return "", nil
+		}
+		path = "."
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if moduleName(filepath.Join(path, "go.mod")) != frameworkModule {
+		return "", fmt.Errorf("%s is not the %s framework: no go.mod with module %s", path, frameworkModule, frameworkModule)
+	}
+	return path, nil
+}
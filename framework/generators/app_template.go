@@ -0,0 +1,171 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// appTemplate шаблон приложения для gorails new --template.
+// YAML-шаблон перечисляет модули, файлы и генераторы:
+//
+//	require:
+//	  - github.com/redis/go-redis/v9@v9.0.5
+//	files:
+//	  config/redis.yml: |
+//	    url: redis://localhost:6379
+//	generate:
+//	  - scaffold post title:string body:text
+//	  - model setting "status:string:default=in review"
+//
+// Строка generate разбивается на аргументы как в shell: кавычки
+// объединяют слова с пробелами и в аргументы не попадают.
+//
+// Go-шаблон — программа, которую new запускает через go run в папке
+// созданного приложения; путь к gorails передается в переменной GORAILS.
+type appTemplate struct {
+	Require  []string          `yaml:"require"`  // модули для go get, module@version
+	Files    map[string]string `yaml:"files"`    // путь в приложении -> содержимое
+	Generate []string          `yaml:"generate"` // аргументы gorails generate
+
+	program string // абсолютный путь к Go-шаблону
+}
+
+// loadAppTemplate читает шаблон приложения; вид определяется расширением файла
+func loadAppTemplate(path string) (*appTemplate, error) {
+	switch filepath.Ext(path) {
+	case ".go":
+		program, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(program); err != nil {
+			return nil, fmt.Errorf("template %s: %v", path, err)
+		}
+		return &appTemplate{program: program}, nil
+	case ".yml", ".yaml":
+	default:
+		return nil, fmt.Errorf("template %s must be a .yml, .yaml or .go file", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %v", path, err)
+	}
+	var tmpl appTemplate
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&tmpl); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %v", path, err)
+	}
+	for file := range tmpl.Files {
+		clean := filepath.Clean(filepath.FromSlash(file))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid template %s: file %s is outside the application", path, file)
+		}
+	}
+	return &tmpl, nil
+}
+
+// addFiles добавляет файлы шаблона к файлам приложения
+func (t *appTemplate) addFiles(files *fileSet) {
+	paths := make([]string, 0, len(t.Files))
+	for path := range t.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		files.add(filepath.Clean(filepath.FromSlash(path)), t.Files[path])
+	}
+}
+
+// apply подключает модули и запускает генераторы или Go-шаблон в созданном приложении
+func (t *appTemplate) apply(files *fileSet) error {
+	gorails, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if t.program != "" {
+		return files.command([]string{"GORAILS=" + gorails}, "go", "run", t.program)
+	}
+
+	for _, module := range t.Require {
+		if err := files.command(nil, "go", "get", module); err != nil {
+			return err
+		}
+	}
+	for _, line := range t.Generate {
+		words, err := shellWords(line)
+		if err != nil {
+			return fmt.Errorf("template generate %q: %v", line, err)
+		}
+		if err := files.command(nil, gorails, append([]string{"generate"}, words...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shellWords разбивает строку на аргументы по правилам shell: пробелы внутри
+// '...' и "..." не разделяют аргументы, \ экранирует следующий символ
+// (внутри "..." — только ", \ и $). Подстановки переменных и команд не выполняются.
+func shellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`, runes[i+1]):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
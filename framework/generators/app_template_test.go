@@ -0,0 +1,37 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShellWords(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"scaffold post title:string body:text", []string{"scaffold", "post", "title:string", "body:text"}},
+		{"  model   post\ttitle  ", []string{"model", "post", "title"}},
+		{`model post "status:string:default=in review"`, []string{"model", "post", "status:string:default=in review"}},
+		{`model post 'status:string:default=it"s'`, []string{"model", "post", `status:string:default=it"s`}},
+		{`model post status:string:default="a b"c`, []string{"model", "post", "status:string:default=a bc"}},
+		{`migration a\ b "x\"y" ""`, []string{"migration", "a b", `x"y`, ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := shellWords(tt.line)
+		if err != nil {
+			t.Errorf("shellWords(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("shellWords(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{`model "post`, `model 'post`, `model post\`} {
+		if _, err := shellWords(line); err == nil {
+			t.Errorf("shellWords(%q): expected an error", line)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package generators
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestCreateNewAppBuilds создает приложение с replace на исходники фреймворка
+// и проверяет, что оно собирается и проходит go vet
+func TestCreateNewAppBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go mod tidy, go build and go vet in a generated application")
+	}
+
+	frameworkPath, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(t.TempDir(), "blog")
+	appOptions := NewAppOptions{FrameworkPath: frameworkPath}
+	if err := CreateNewApp(root, appOptions, Options{Out: io.Discard}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %v: %v\n%s", args, err, out)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package generators
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go-rails/framework/inflector"
+)
+
+// DestroyOptions настройки команд destroy
+type DestroyOptions struct {
+	// Force удаляет и файлы, измененные после генерации, и уже примененные миграции
+	Force bool
+	// Pretend только выводит действия, ничего не удаляя
+	Pretend bool
+	// Out отчет о действиях; по умолчанию os.Stdout
+	Out io.Writer
+	// AppliedVersions возвращает версии из schema_migrations приложения.
+	// Вызывается, только если удаляются миграции и Force не задан.
+	AppliedVersions func() (map[string]bool, error)
+}
+
+// ModifiedError сообщает, что файлы изменены после генерации и destroy их не удалил
+type ModifiedError struct {
+	Paths []string
+}
+
+func (e *ModifiedError) Error() string {
+	return fmt.Sprintf("files were modified since generation (use --force to remove them anyway): %s",
+		strings.Join(e.Paths, ", "))
+}
+
+// AppliedMigrationError сообщает, что удаляемые миграции еще применены:
+// без файла их запись осталась бы в schema_migrations
+type AppliedMigrationError struct {
+	Paths []string
+}
+
+func (e *AppliedMigrationError) Error() string {
+	return fmt.Sprintf("migrations are still applied, roll them back first with db rollback or db migrate --to "+
+		"(use --force to remove them anyway): %s", strings.Join(e.Paths, ", "))
+}
+
+// DestroyController удаляет контроллер, созданный generate controller
+func DestroyController(controllerName string, options DestroyOptions) error {
+	return removeFiles([]string{controllerPath(controllerName)}, nil, options)
+}
+
+// DestroyModel удаляет модель и миграцию create_<table>
+func DestroyModel(modelName string, options DestroyOptions) error {
+	migrations, err := migrationPaths("", "create_"+inflector.Tableize(modelName))
+	if err != nil {
+		return err
+	}
+	return removeFiles([]string{modelPath(modelName)}, migrations, options)
+}
+
+// DestroyMigration удаляет миграцию по имени, версия в имени файла не нужна
+func DestroyMigration(migrationName string, options DestroyOptions) error {
+	migrations, err := migrationPaths("", migrationName)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return fmt.Errorf("migration %s not found", migrationName)
+	}
+	return removeFiles(nil, migrations, options)
+}
+
+// DestroyScaffold удаляет всё, что создал generate scaffold,
+// и убирает маршруты ресурса из routes/routes.go
+func DestroyScaffold(modelName string, options DestroyOptions) error {
+	resource := newScaffoldResource("", modelName, nil)
+
+	migrations, err := migrationPaths("", "create_"+resource.Table)
+	if err != nil {
+		return err
+	}
+	routesPath := filepath.Join("routes", "routes.go")
+	routes, err := removeResourceRoute(routesPath, resource.routeLine())
+	if err != nil {
+		return err
+	}
+
+	paths := append(resource.paths(), modelPath(modelName))
+	if err := removeFiles(paths, migrations, options); err != nil {
+		return err
+	}
+	if routes == "" {
+		return nil
+	}
+	destroyFileSet(options).say(ActionUpdate, routesPath)
+	if options.Pretend {
+		return nil
+	}
+	return os.WriteFile(routesPath, []byte(routes), 0644)
+}
+
+// migrationPaths находит файлы db/migrate/<version>_<name>.go в приложении
+// в папке root ("" — текущая)
+func migrationPaths(root, migrationName string) ([]string, error) {
+	name := inflector.Underscore(migrationName) + ".go"
+	matches, err := filepath.Glob(filepath.Join(root, "db", "migrate", "*_"+name))
+	if err != nil {
+		return nil, err
+	}
+	// *_create_posts.go подходит и под <version>_recreate_and_create_posts.go
+	var paths []string
+	for _, path := range matches {
+		if parts := strings.SplitN(filepath.Base(path), "_", 2); parts[1] == name {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// removeFiles удаляет существующие файлы из paths и migrations. Если хотя бы
+// один из них изменен после генерации или миграция применена, без Force
+// ничего не удаляется.
+func removeFiles(paths, migrations []string, options DestroyOptions) error {
+	if !options.Force {
+		if err := checkApplied(migrations, options); err != nil {
+			return err
+		}
+	}
+
+	manifest, err := readManifest("")
+	if err != nil {
+		return err
+	}
+
+	var existing, modified []string
+	for _, path := range append(paths, migrations...) {
+		ok, err := unchanged(manifest, path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		existing = append(existing, path)
+		if !ok {
+			modified = append(modified, path)
+		}
+	}
+
+	files := destroyFileSet(options)
+	blocked := len(modified) > 0 && !options.Force
+	for _, path := range existing {
+		if blocked && slices.Contains(modified, path) {
+			files.say(ActionConflict, path)
+		} else {
+			files.say(ActionRemove, path)
+		}
+	}
+	if options.Pretend {
+		return nil
+	}
+	if blocked {
+		return &ModifiedError{Paths: modified}
+	}
+
+	for _, path := range existing {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		delete(manifest, filepath.ToSlash(path))
+	}
+	return writeManifest("", manifest)
+}
+
+// checkApplied проверяет, что ни одна из удаляемых миграций не применена
+func checkApplied(migrations []string, options DestroyOptions) error {
+	if len(migrations) == 0 || options.AppliedVersions == nil {
+		return nil
+	}
+	versions, err := options.AppliedVersions()
+	if err != nil {
+		return fmt.Errorf("failed to check applied migrations (use --force to skip the check): %v", err)
+	}
+
+	var applied []string
+	for _, path := range migrations {
+		version, _, _ := strings.Cut(filepath.Base(path), "_")
+		if versions[version] {
+			applied = append(applied, path)
+		}
+	}
+	if len(applied) > 0 {
+		return &AppliedMigrationError{Paths: applied}
+	}
+	return nil
+}
+
+// destroyFileSet возвращает fileSet для отчета о действиях destroy
+func destroyFileSet(options DestroyOptions) *fileSet {
+	return newFileSet("", Options{Force: options.Force, Pretend: options.Pretend, Out: options.Out})
+}
+
+// removeResourceRoute возвращает routes без строки маршрутов ресурса,
+// добавленной generate scaffold, или "", если строки нет
+func removeResourceRoute(path, line string) (string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read routes: %v", err)
+	}
+	routes := string(content)
+	if !strings.Contains(routes, line) {
+		return "", nil
+	}
+	return strings.Replace(routes, line, "", 1), nil
+}
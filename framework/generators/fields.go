@@ -0,0 +1,241 @@
+package generators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-rails/framework/inflector"
+)
+
+// Типы полей, описывающие связи между моделями
+const (
+	referencesType = "references" // author:references — колонка author_id и поле Author
+	belongsToType  = "belongs_to" // синоним references
+	hasManyType    = "has_many"   // comments:has_many — поле Comments []Comment
+	many2manyType  = "many2many"  // tags:many2many — поле Tags []Tag и таблица связи
+)
+
+// modelField поле модели из командной строки generate model:
+//
+//	name:type[{limit|precision,scale}][:modifier...]
+//
+// Модификаторы: uniq, index, null, default=<value>. Например
+// email:string:uniq, bio:text:null, status:string:default=draft, price:decimal{10,2}.
+// Экспортированные методы доступны в шаблонах генераторов.
+type modelField struct {
+	Name       string
+	Type       string
+	Size       string // string{100} -> 100
+	Scale      string // decimal{10,2} -> Size 10, Scale 2
+	Unique     bool
+	Index      bool
+	Null       bool
+	Default    string
+	HasDefault bool
+}
+
+// parseFields разбирает аргументы name:type:modifiers; тип по умолчанию — string
+func parseFields(args []string) ([]modelField, error) {
+	fields := make([]modelField, 0, len(args))
+	for _, arg := range args {
+		field, err := parseField(arg)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// parseField разбирает одно поле
+func parseField(arg string) (modelField, error) {
+	parts := strings.Split(arg, ":")
+	if parts[0] == "" {
+		return modelField{}, fmt.Errorf("invalid field %q, expected name:type", arg)
+	}
+	field := modelField{Name: inflector.Underscore(parts[0]), Type: "string"}
+
+	if len(parts) > 1 && parts[1] != "" {
+		field.Type = parts[1]
+		if open := strings.Index(field.Type, "{"); open >= 0 {
+			if !strings.HasSuffix(field.Type, "}") {
+				return modelField{}, fmt.Errorf("invalid field %q: unterminated {", arg)
+			}
+			params := strings.Split(field.Type[open+1:len(field.Type)-1], ",")
+			field.Type = field.Type[:open]
+			field.Size = strings.TrimSpace(params[0])
+			if len(params) > 1 {
+				field.Scale = strings.TrimSpace(params[1])
+			}
+		}
+	}
+	if field.Type == belongsToType {
+		field.Type = referencesType
+	}
+
+	for _, modifier := range parts[min(len(parts), 2):] {
+		switch {
+		case modifier == "uniq" || modifier == "unique":
+			field.Unique = true
+		case modifier == "index":
+			field.Index = true
+		case modifier == "null":
+			field.Null = true
+		case strings.HasPrefix(modifier, "default="):
+			field.Default = strings.TrimPrefix(modifier, "default=")
+			field.HasDefault = true
+		default:
+			return modelField{}, fmt.Errorf("invalid field %q: unknown modifier %q", arg, modifier)
+		}
+	}
+	if field.IsCollection() && (field.Unique || field.Index || field.Null || field.HasDefault) {
+		return modelField{}, fmt.Errorf("invalid field %q: %s does not take modifiers", arg, field.Type)
+	}
+	return field, nil
+}
+
+// GormTag настройки колонки для тега gorm
+func (f modelField) GormTag() string {
+	var settings []string
+	switch {
+	case f.Type == "text":
+		settings = append(settings, "type:text")
+	case (f.Type == "decimal" || f.Type == "numeric") && f.Size != "":
+		precision := f.Size
+		if f.Scale != "" {
+			precision += "," + f.Scale
+		}
+		settings = append(settings, "type:decimal("+precision+")")
+	case f.Size != "":
+		settings = append(settings, "size:"+f.Size)
+	}
+	if !f.Null {
+		settings = append(settings, "not null")
+	}
+	if f.HasDefault {
+		settings = append(settings, "default:"+f.defaultSQL())
+	}
+	switch {
+	case f.Unique:
+		settings = append(settings, "unique_index")
+	case f.Index || f.IsReference():
+		settings = append(settings, "index")
+	}
+	return strings.Join(settings, ";")
+}
+
+// defaultSQL значение по умолчанию в виде литерала SQL
+func (f modelField) defaultSQL() string {
+	if getGoType(f.Type) == "string" && !f.IsReference() {
+		return "'" + strings.ReplaceAll(f.Default, "'", "''") + "'"
+	}
+	return f.Default
+}
+
+// GoType тип поля в Go. Необязательные поля и поля со значением по умолчанию,
+// кроме строк, становятся указателями: иначе нельзя записать NULL, а нулевое
+// значение (false, 0) GORM не вставляет и подставляет значение по умолчанию.
+func (f modelField) GoType() string {
+	typ := getGoType(f.Type)
+	if f.IsReference() {
+		typ = "uint"
+	}
+	if (f.Null || f.HasDefault) && typ != "string" {
+		typ = "*" + typ
+	}
+	return typ
+}
+
+// IsReference сообщает, что поле — внешний ключ на другую модель
+func (f modelField) IsReference() bool {
+	return f.Type == referencesType
+}
+
+// IsCollection сообщает, что поле — коллекция связанных записей без своей колонки
+func (f modelField) IsCollection() bool {
+	return f.Type == hasManyType || f.Type == many2manyType
+}
+
+// Column имя колонки в таблице модели
+func (f modelField) Column() string {
+	if f.IsReference() {
+		return f.Name + "_id"
+	}
+	return f.Name
+}
+
+// Target имя связанной модели: author -> Author, comments -> Comment
+func (f modelField) Target() string {
+	if f.IsCollection() {
+		return inflector.Classify(f.Name)
+	}
+	return inflector.Camelize(f.Name)
+}
+
+// JoinTable имя таблицы связи many2many: post + tags -> post_tags
+func (f modelField) JoinTable(model string) string {
+	return inflector.Underscore(model) + "_" + f.Name
+}
+
+// IsMany2Many сообщает, что поле — связь многие-ко-многим через таблицу связи
+func (f modelField) IsMany2Many() bool {
+	return f.Type == many2manyType
+}
+
+// Optional сообщает, что тип поля в Go — указатель (см. GoType)
+func (f modelField) Optional() bool {
+	return strings.HasPrefix(f.GoType(), "*")
+}
+
+// GoName имя поля колонки в структуре: author -> AuthorID, title -> Title
+func (f modelField) GoName() string {
+	return inflector.Camelize(f.Column())
+}
+
+// FieldName имя поля связи в структуре: author -> Author, tags -> Tags
+func (f modelField) FieldName() string {
+	return inflector.Camelize(f.Name)
+}
+
+// Validations правила validation для поля: Presence для обязательных строк
+// и ссылок, Length по размеру строки, Uniqueness для uniq
+func (f modelField) Validations() []string {
+	if f.IsCollection() {
+		return nil
+	}
+	name := f.GoName()
+	var rules []string
+	if !f.Null && !f.HasDefault && (f.IsReference() || f.GoType() == "string") {
+		rules = append(rules, fmt.Sprintf("validation.Presence(%q)", name))
+	}
+	if f.GoType() == "string" && f.Type != "text" && f.Size != "" {
+		rules = append(rules, fmt.Sprintf("validation.Length(%q, 0, %s)", name, f.Size))
+	}
+	if f.Unique {
+		rules = append(rules, fmt.Sprintf("validation.Uniqueness(%q)", name))
+	}
+	return rules
+}
+
+// SampleJSON пример значения поля в запросе для сгенерированных тестов
+func (f modelField) SampleJSON() string {
+	if f.IsReference() {
+		return "1"
+	}
+	switch getGoType(f.Type) {
+	case "int", "int64":
+		return "1"
+	case "float64":
+		return "1.5"
+	case "bool":
+		return "true"
+	case "time.Time":
+		return `"2024-01-01T00:00:00Z"`
+	}
+	sample := "My" + inflector.Camelize(f.Name)
+	if size, err := strconv.Atoi(f.Size); err == nil && size < len(sample) {
+		sample = sample[:size]
+	}
+	return fmt.Sprintf("%q", sample)
+}
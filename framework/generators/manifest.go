@@ -0,0 +1,70 @@
+package generators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// manifestPath файл с контрольными суммами сгенерированных файлов.
+// По нему destroy отличает нетронутые файлы от измененных после генерации;
+// его стоит хранить в репозитории приложения.
+var manifestPath = filepath.Join(".gorails", "generated.json")
+
+// unchanged сообщает, что файл не менялся с момента генерации.
+// Файлы, которых нет в манифесте, считаются измененными.
+func unchanged(manifest map[string]string, path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	sum, ok := manifest[filepath.ToSlash(path)]
+	return ok && sum == checksum(content), nil
+}
+
+// readManifest читает манифест приложения в папке root ("" — текущая);
+// отсутствующий файл означает пустой манифест
+func readManifest(root string) (map[string]string, error) {
+	manifest := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(root, manifestPath))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", manifestPath, err)
+	}
+	return manifest, nil
+}
+
+// writeManifest сохраняет манифест приложения в папке root, а пустой — удаляет
+func writeManifest(root string, manifest map[string]string) error {
+	path := filepath.Join(root, manifestPath)
+	if len(manifest) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		// Папка .gorails больше не нужна, если в ней ничего не осталось
+		os.Remove(filepath.Dir(path))
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// checksum возвращает SHA-256 содержимого в hex
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package generators
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// update перезаписывает testdata/*.golden текущим результатом:
+//
+//	go test ./framework/generators -run TestRender -update
+var update = flag.Bool("update", false, "rewrite testdata/*.golden with the current output")
+
+// goldenFields поля модели, общие для всех проверок
+var goldenFields = []string{
+	"title:string{200}:uniq",
+	"body:text:null",
+	"price:decimal{10,2}",
+	"status:string:default=draft",
+	"published_at:datetime:null",
+	"author:references",
+	"tags:many2many",
+	"comments:has_many",
+	"subtitle:string:null",
+}
+
+// goldenApp данные шаблонов приложения для проверки gorails new
+var goldenApp = appData{
+	Name:          "blog",
+	FrameworkPath: "/src/go-rails",
+	Database:      "sqlite3",
+	Auth:          true,
+	Seeds:         true,
+}
+
+const goldenVersion = "20240102030405"
+
+// renderCase шаблон и данные для одного golden-файла
+type renderCase struct {
+	golden   string
+	template string
+	data     interface{}
+}
+
+func TestRender(t *testing.T) {
+	fields, err := parseFields(goldenFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource := newScaffoldResource("blog", "blog_post", fields)
+
+	cases := []renderCase{
+		{"controller", "controller.go.tmpl", controllerData{Name: "blog_pages", Type: "BlogPages"}},
+		{"model", "model.go.tmpl", modelData{
+			Name:      "blog_post",
+			Model:     "BlogPost",
+			Table:     "blog_posts",
+			Validator: "blogPostValidator",
+			Fields:    fields,
+		}},
+		{"model_soft_delete", "model.go.tmpl", modelData{
+			Name:       "note",
+			Model:      "Note",
+			Table:      "notes",
+			Validator:  "noteValidator",
+			Fields:     fields[:1],
+			SoftDelete: true,
+		}},
+		{"migration", "migration.go.tmpl", migrationData{Name: "add_status_to_posts", Version: goldenVersion}},
+		{"create_table_migration", "create_table_migration.go.tmpl",
+			newCreateTableData("create_blog_posts", goldenVersion, "blog_post", fields, ModelOptions{})},
+		{"create_table_migration_soft_delete", "create_table_migration.go.tmpl",
+			newCreateTableData("create_notes", goldenVersion, "note", fields[:1], ModelOptions{SoftDelete: true})},
+		{"scaffold_controller", "scaffold/controller.go.tmpl", resource},
+		{"scaffold_controller_test", "scaffold/controller_test.go.tmpl", resource},
+		{"scaffold_serializer", "scaffold/serializer.go.tmpl", resource},
+		{"new_create_users", "app/create_users.go.tmpl", migrationData{Name: "create_users", Version: goldenVersion}},
+	}
+
+	paths := make([]string, 0, len(appFiles))
+	for path := range appFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		cases = append(cases, renderCase{"new_" + strings.ReplaceAll(path, "/", "_"), appFiles[path], goldenApp})
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			got, err := render(tc.template, tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tc.golden, got)
+		})
+	}
+}
+
+// TestRenderScaffoldRoutes проверяет маршруты ресурса, добавленные
+// в routes/routes.go нового приложения
+func TestRenderScaffoldRoutes(t *testing.T) {
+	routes, err := render(appFiles["routes/routes.go"], goldenApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "routes.go")
+	if err := os.WriteFile(path, []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource := newScaffoldResource("blog", "blog_post", nil)
+	got, err := addResourceRoute(path, resource.routeLine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "scaffold_routes", got)
+
+	// Повторный scaffold не добавляет маршрут второй раз
+	if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := addResourceRoute(path, resource.routeLine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != got {
+		t.Errorf("route added twice:\n%s", again)
+	}
+}
+
+// checkGolden сравнивает got с testdata/<name>.golden или перезаписывает его с -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        lines(string(want)),
+			B:        lines(got),
+			FromFile: path,
+			ToFile:   "rendered",
+			Context:  3,
+		})
+		t.Errorf("output differs from %s (run go test -update if the change is intended):\n%s", path, diff)
+	}
+}
@@ -0,0 +1,197 @@
+package generators
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-rails/framework/inflector"
+)
+
+// GenerateScaffold генерирует ресурс целиком: модель с миграцией, CRUD-контроллер,
+// сериализатор, тест контроллера и маршруты в routes/routes.go.
+// Запускается в корне приложения, созданного gorails new.
+func GenerateScaffold(modelName string, args []string, modelOptions ModelOptions, options Options) error {
+	module := appModule()
+	if module == "" {
+		return fmt.Errorf("go.mod not found: run generate scaffold in the application root")
+	}
+	fields, err := parseFields(args)
+	if err != nil {
+		return err
+	}
+
+	files := newFileSet("", options)
+	if err := addModel(files, modelName, fields, modelOptions); err != nil {
+		return err
+	}
+
+	resource := newScaffoldResource(module, modelName, fields)
+	for i, path := range resource.paths() {
+		content, err := render(scaffoldTemplates[i], resource)
+		if err != nil {
+			return err
+		}
+		files.add(path, content)
+	}
+
+	routesPath := filepath.Join("routes", "routes.go")
+	routes, err := addResourceRoute(routesPath, resource.routeLine())
+	if err != nil {
+		return err
+	}
+	files.update(routesPath, routes)
+	return files.run()
+}
+
+// scaffoldTemplates шаблоны файлов из scaffoldResource.paths, в том же порядке
+var scaffoldTemplates = []string{
+	"scaffold/controller.go.tmpl",
+	"scaffold/controller_test.go.tmpl",
+	"scaffold/serializer.go.tmpl",
+}
+
+// scaffoldResource данные шаблонов scaffold: имена, общие для файлов ресурса
+type scaffoldResource struct {
+	Module string // модуль приложения из go.mod
+	Model  string // BlogPost
+	Name   string // blog_post
+	Table  string // blog_posts
+	Fields []modelField
+}
+
+// newScaffoldResource вычисляет имена ресурса по имени модели в любой записи
+func newScaffoldResource(module, modelName string, fields []modelField) scaffoldResource {
+	return scaffoldResource{
+		Module: module,
+		Model:  inflector.Camelize(modelName),
+		Name:   inflector.Underscore(modelName),
+		Table:  inflector.Tableize(modelName),
+		Fields: fields,
+	}
+}
+
+// paths файлы ресурса помимо модели и миграции: контроллер, его тест и сериализатор
+func (r scaffoldResource) paths() []string {
+	return []string{
+		filepath.Join("app", "controllers", r.Table+"_controller.go"),
+		filepath.Join("app", "controllers", r.Table+"_controller_test.go"),
+		filepath.Join("app", "serializers", r.Name+"_serializer.go"),
+	}
+}
+
+// Controller имя типа контроллера: BlogPostsController
+func (r scaffoldResource) Controller() string {
+	return inflector.Camelize(r.Table) + "Controller"
+}
+
+// Variable имя переменной для записи, не совпадающее с импортами сгенерированных файлов
+func (r scaffoldResource) Variable() string {
+	return safeIdentifier(inflector.LowerCamelize(r.Name))
+}
+
+// PluralVariable имя для списка записей и функций ресурса: blogPosts
+func (r scaffoldResource) PluralVariable() string {
+	return safeIdentifier(inflector.LowerCamelize(r.Table))
+}
+
+// safeIdentifier добавляет суффикс к имени, совпадающему с ключевым словом
+// или импортом сгенерированных файлов
+func safeIdentifier(name string) string {
+	if token.IsKeyword(name) {
+		return name + "Record"
+	}
+	switch name {
+	case "models", "serializers", "base", "callbacks", "database", "validation",
+		"gin", "http", "strconv", "time", "router", "json", "bytes", "io", "testing",
+		"c", "p", "rc", "id", "err", "ok", "page", "params", "records", "result":
+		return name + "Record"
+	}
+	return name
+}
+
+// Columns поля модели, у которых есть колонка в таблице
+func (r scaffoldResource) Columns() []modelField {
+	var columns []modelField
+	for _, field := range r.Fields {
+		if !field.IsCollection() {
+			columns = append(columns, field)
+		}
+	}
+	return columns
+}
+
+// UsesTime сообщает, есть ли среди колонок поля time.Time
+func (r scaffoldResource) UsesTime() bool {
+	for _, field := range r.Columns() {
+		if strings.Contains(field.GoType(), "time.Time") {
+			return true
+		}
+	}
+	return false
+}
+
+// Required первая обязательная колонка (с правилом Presence) или пусто
+func (r scaffoldResource) Required() string {
+	for _, field := range r.Columns() {
+		if rules := field.Validations(); len(rules) > 0 && strings.HasPrefix(rules[0], "validation.Presence") {
+			return field.Column()
+		}
+	}
+	return ""
+}
+
+// routeLine строка, подключающая маршруты ресурса в apiRoutes
+func (r scaffoldResource) routeLine() string {
+	return fmt.Sprintf("\trouter.Resources(api, %q, controllers.New%s(db))\n", r.Table, r.Controller())
+}
+
+// appModule возвращает имя модуля приложения из go.mod в текущей папке
+func appModule() string {
+	return moduleName("go.mod")
+}
+
+// moduleName возвращает имя модуля из файла go.mod
+func moduleName(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
+
+// addResourceRoute возвращает файл маршрутов со строкой line в конце функции apiRoutes
+func addResourceRoute(path, line string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read routes: %v", err)
+	}
+	routes := string(content)
+	if strings.Contains(routes, line) {
+		return routes, nil
+	}
+
+	start := strings.Index(routes, "func apiRoutes(")
+	if start < 0 {
+		return "", fmt.Errorf("%s has no apiRoutes function, add the route manually:\n%s", path, line)
+	}
+	end := strings.Index(routes[start:], "\n}\n")
+	if end < 0 {
+		return "", fmt.Errorf("%s: apiRoutes is not terminated, add the route manually:\n%s", path, line)
+	}
+	at := start + end + 1
+
+	return routes[:at] + line + routes[at:], nil
+}
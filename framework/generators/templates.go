@@ -0,0 +1,66 @@
+package generators
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"go-rails/framework/inflector"
+)
+
+// templateFS встроенные шаблоны генераторов
+//
+//go:embed templates
+var templateFS embed.FS
+
+// overridesDir папка приложения с шаблонами, заменяющими встроенные:
+// lib/templates/model.go.tmpl используется вместо templates/model.go.tmpl
+var overridesDir = filepath.Join("lib", "templates")
+
+// templateFuncs функции, доступные в шаблонах
+var templateFuncs = template.FuncMap{
+	"camelize":      inflector.Camelize,
+	"lowerCamelize": inflector.LowerCamelize,
+	"underscore":    inflector.Underscore,
+	"humanize":      inflector.Humanize,
+	"pluralize":     inflector.Pluralize,
+	"singularize":   inflector.Singularize,
+	"lower":         strings.ToLower,
+}
+
+// render выполняет шаблон name с данными data. Шаблон берется из lib/templates
+// приложения, если он там есть, иначе встроенный. Go-код форматируется go/format.
+func render(name string, data interface{}) (string, error) {
+	path := filepath.Join(overridesDir, filepath.FromSlash(name))
+	source, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		path = "templates/" + name
+		source, err = templateFS.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("template %s: %v", name, err)
+	}
+
+	tmpl, err := template.New(path).Funcs(templateFuncs).Parse(string(source))
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return out.String(), nil
+	}
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("template %s produced invalid Go code: %v\n%s", path, err, out.String())
+	}
+	return string(formatted), nil
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"go-rails/framework/database"
 	"go-rails/framework/models"
+	"go-rails/framework/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,7 +33,7 @@ func (ac *AuthController) Login(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := ac.DB.Where("email = ?", loginData.Email).First(&user).Error; err != nil {
+	if err := ac.Conn(c).Where("email = ?", loginData.Email).First(&user).Error; err != nil {
 		ac.Unauthorized(c, "Invalid credentials")
 		return
 	}
@@ -57,23 +58,17 @@ func (ac *AuthController) Login(c *gin.Context) {
 
 // Register обрабатывает регистрацию пользователя
 func (ac *AuthController) Register(c *gin.Context) {
-	var user models.User
+	var params userParams
 
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&params); err != nil {
 		ac.ErrorResponse(c, 400, "Invalid registration data")
 		return
 	}
+	user := params.user()
 
 	// Валидация
-	if errors := user.Validate(); len(errors) > 0 {
-		ac.ValidationError(c, errors)
-		return
-	}
-
-	// Проверяем, что email уникален
-	var existingUser models.User
-	if err := ac.DB.Where("email = ?", user.Email).First(&existingUser).Error; err == nil {
-		ac.ErrorResponse(c, 422, "Email already exists")
+	if errs := models.Validate(&user, validation.Context{On: validation.OnCreate, DB: ac.Conn(c)}); errs.Any() {
+		ac.ValidationError(c, errs)
 		return
 	}
 
@@ -83,8 +78,18 @@ func (ac *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	// Создаем пользователя
-	if err := ac.DB.Create(&user).Error; err != nil {
+	// Уникальность email гарантирует индекс: проверка перед вставкой
+	// не защищает от одновременной регистрации с тем же адресом.
+	// Внутри транзакции запроса вставка выполняется в savepoint, чтобы
+	// ошибка индекса не прерывала всю транзакцию (PostgreSQL)
+	err := ac.Conn(c).Transaction(func(tx *database.Database) error {
+		return tx.Create(&user).Error
+	})
+	if database.IsUniqueViolation(err) {
+		ac.ValidationError(c, validation.Errors{"email": {"Email has already been taken"}})
+		return
+	}
+	if err != nil {
 		ac.ErrorResponse(c, 500, "Failed to create user")
 		return
 	}
@@ -1,7 +1,11 @@
 package controllers
 
 import (
+	"errors"
+
 	"go-rails/framework/database"
+	"go-rails/framework/middleware"
+	"go-rails/framework/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +20,29 @@ func NewBaseController(db *database.Database) *BaseController {
 	return &BaseController{DB: db}
 }
 
+// Conn возвращает базу для текущего запроса: транзакцию middleware.Transactional,
+// основную базу, если запрос помечен middleware.DatabaseSelector или UsePrimary,
+// иначе базу с чтением из реплик
+func (bc *BaseController) Conn(c *gin.Context) *database.Database {
+	if tx, ok := c.Get(middleware.TransactionKey); ok {
+		return tx.(*database.Database)
+	}
+	if c.GetBool(middleware.PrimaryDatabaseKey) {
+		return bc.DB.Primary()
+	}
+	return bc.DB
+}
+
+// UsePrimary направляет все дальнейшие чтения в рамках запроса в основную базу
+func (bc *BaseController) UsePrimary(c *gin.Context) {
+	c.Set(middleware.PrimaryDatabaseKey, true)
+}
+
+// Database возвращает именованную базу из секции databases конфигурации
+func (bc *BaseController) Database(name string) (*database.Database, error) {
+	return bc.DB.Named(name)
+}
+
 // SuccessResponse возвращает успешный ответ
 func (bc *BaseController) SuccessResponse(c *gin.Context, data interface{}) {
 	c.JSON(200, gin.H{
@@ -24,6 +51,14 @@ func (bc *BaseController) SuccessResponse(c *gin.Context, data interface{}) {
 	})
 }
 
+// Created возвращает ответ 201 с созданной записью
+func (bc *BaseController) Created(c *gin.Context, data interface{}) {
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
 // ErrorResponse возвращает ответ с ошибкой
 func (bc *BaseController) ErrorResponse(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, gin.H{
@@ -32,14 +67,35 @@ func (bc *BaseController) ErrorResponse(c *gin.Context, statusCode int, message
 	})
 }
 
-// ValidationError возвращает ошибку валидации
-func (bc *BaseController) ValidationError(c *gin.Context, errors map[string]string) {
+// ValidationError возвращает ошибку валидации: по каждому полю список сообщений
+func (bc *BaseController) ValidationError(c *gin.Context, errors validation.Errors) {
 	c.JSON(422, gin.H{
 		"success": false,
 		"errors":  errors,
 	})
 }
 
+// StaleObject возвращает ошибку 409 с текущей версией записи,
+// чтобы клиент мог перечитать её и повторить изменение
+func (bc *BaseController) StaleObject(c *gin.Context, err *database.StaleObjectError) {
+	c.JSON(409, gin.H{
+		"success":      false,
+		"error":        "Record was modified by another request",
+		"lock_version": err.CurrentVersion,
+	})
+}
+
+// SaveError отвечает на ошибку сохранения: 409 для устаревшей версии записи,
+// иначе 500 с сообщением message
+func (bc *BaseController) SaveError(c *gin.Context, err error, message string) {
+	var stale *database.StaleObjectError
+	if errors.As(err, &stale) {
+		bc.StaleObject(c, stale)
+		return
+	}
+	bc.ErrorResponse(c, 500, message)
+}
+
 // NotFound возвращает ошибку 404
 func (bc *BaseController) NotFound(c *gin.Context, message string) {
 	if message == "" {
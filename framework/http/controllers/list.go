@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-rails/framework/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListMeta сведения о странице для ответа Index
+type ListMeta struct {
+	Total      int    `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListQuery разбирает стандартные параметры списка:
+//
+//	?page=2&per_page=50
+//	?sort=name,-created_at
+//	?filter[name]=Alice&filter[age][gte]=18&filter[role][in]=admin,editor
+//	?after=<cursor>
+//
+// Допустимость полей сортировки и фильтров проверяет репозиторий.
+func (bc *BaseController) ListQuery(c *gin.Context) (database.Query, error) {
+	var q database.Query
+	params := c.Request.URL.Query()
+
+	var err error
+	if q.Page, err = positiveParam(params, "page"); err != nil {
+		return q, err
+	}
+	if q.PerPage, err = positiveParam(params, "per_page"); err != nil {
+		return q, err
+	}
+	q.Sort = database.ParseSort(params.Get("sort"))
+	q.After = params.Get("after")
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		filter, err := parseFilter(key, params.Get(key))
+		if err != nil {
+			return q, err
+		}
+		q.Filters = append(q.Filters, filter)
+	}
+	return q, nil
+}
+
+// PageResponse возвращает страницу списка вместе с meta и ссылками на соседние страницы
+func (bc *BaseController) PageResponse(c *gin.Context, data interface{}, meta ListMeta) {
+	links := gin.H{"self": c.Request.URL.String(), "next": nil, "prev": nil}
+
+	switch {
+	case meta.NextCursor != "":
+		links["next"] = pageLink(c, "after", meta.NextCursor)
+	case meta.Page > 0 && meta.Page < meta.TotalPages:
+		links["next"] = pageLink(c, "page", strconv.Itoa(meta.Page+1))
+	}
+	if meta.Page > 1 {
+		links["prev"] = pageLink(c, "page", strconv.Itoa(meta.Page-1))
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    data,
+		"meta":    meta,
+		"links":   links,
+	})
+}
+
+// Paginate выполняет стандартное действие Index: разбирает параметры запроса,
+// выбирает страницу из репозитория и отвечает конвертом с meta и links.
+// С параметром after (в том числе пустым — первая страница) используется
+// выборка по ключу, иначе по номеру страницы.
+func Paginate[T any](bc *BaseController, c *gin.Context, repo *database.Repository[T]) {
+	page, ok := ListPage(bc, c, repo)
+	if !ok {
+		return
+	}
+
+	items := page.Items
+	if items == nil {
+		items = []T{}
+	}
+	bc.PageResponse(c, items, PageMeta(page))
+}
+
+// ListPage выбирает страницу так же, как Paginate, но не отвечает данными:
+// пригодится, если записи нужно преобразовать перед ответом PageResponse.
+// При ошибке отвечает 400 или 500 и возвращает false.
+func ListPage[T any](bc *BaseController, c *gin.Context, repo *database.Repository[T]) (*database.Page[T], bool) {
+	q, err := bc.ListQuery(c)
+	if err != nil {
+		bc.ErrorResponse(c, 400, err.Error())
+		return nil, false
+	}
+
+	var page *database.Page[T]
+	if c.Request.URL.Query().Has("after") {
+		page, err = repo.ListAfter(q)
+	} else {
+		page, err = repo.List(q)
+	}
+
+	var queryErr *database.QueryError
+	if errors.As(err, &queryErr) {
+		bc.ErrorResponse(c, 400, queryErr.Error())
+		return nil, false
+	}
+	if err != nil {
+		bc.ErrorResponse(c, 500, "Failed to fetch records")
+		return nil, false
+	}
+	return page, true
+}
+
+// PageMeta возвращает meta для PageResponse
+func PageMeta[T any](page *database.Page[T]) ListMeta {
+	return ListMeta{
+		Total:      page.Total,
+		Page:       page.Page,
+		PerPage:    page.PerPage,
+		TotalPages: page.TotalPages,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// positiveParam читает необязательный положительный целочисленный параметр
+func positiveParam(params url.Values, name string) (int, error) {
+	value := params.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+// parseFilter разбирает ключ filter[field] или filter[field][op]
+func parseFilter(key, value string) (database.Filter, error) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]"), "][")
+	if len(parts) == 0 || len(parts) > 2 || parts[0] == "" {
+		return database.Filter{}, fmt.Errorf("invalid filter parameter %q", key)
+	}
+
+	filter := database.Filter{Field: parts[0], Op: database.OpEq, Value: value}
+	if len(parts) == 2 {
+		filter.Op = database.Operator(parts[1])
+	}
+
+	switch filter.Op {
+	case database.OpIn:
+		filter.Value = strings.Split(value, ",")
+	case database.OpIsNull:
+		isNull, err := strconv.ParseBool(value)
+		if err != nil {
+			return database.Filter{}, fmt.Errorf("%s must be true or false", key)
+		}
+		filter.Value = isNull
+	}
+	return filter, nil
+}
+
+// pageLink возвращает текущий URL с замененным параметром пагинации
+func pageLink(c *gin.Context, name, value string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("page")
+	query.Del("after")
+	query.Set(name, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
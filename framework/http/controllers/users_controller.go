@@ -6,6 +6,7 @@ import (
 
 	"go-rails/framework/database"
 	"go-rails/framework/models"
+	"go-rails/framework/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,16 +23,34 @@ func NewUsersController(db *database.Database) *UsersController {
 	}
 }
 
-// Index возвращает список всех пользователей
-func (uc *UsersController) Index(c *gin.Context) {
-	var users []models.User
+// userParams поля пользователя, принимаемые при создании и регистрации
+type userParams struct {
+	Name                 string `json:"name"`
+	Email                string `json:"email"`
+	Password             string `json:"password"`
+	PasswordConfirmation string `json:"password_confirmation"`
+}
 
-	if err := uc.DB.Find(&users).Error; err != nil {
-		uc.ErrorResponse(c, 500, "Failed to fetch users")
-		return
+// user создает модель из параметров запроса
+func (p userParams) user() models.User {
+	return models.User{
+		Name:                 p.Name,
+		Email:                p.Email,
+		Password:             p.Password,
+		PasswordConfirmation: p.PasswordConfirmation,
 	}
+}
 
-	uc.SuccessResponse(c, users)
+// usersListOptions поля, по которым Index разрешает сортировку и фильтры
+var usersListOptions = database.RepositoryOptions{
+	SortFields:   []string{"id", "name", "email", "created_at"},
+	FilterFields: []string{"name", "email", "created_at"},
+}
+
+// Index возвращает страницу пользователей с сортировкой и фильтрами
+// (?page, ?per_page, ?sort, ?filter[...], ?after)
+func (uc *UsersController) Index(c *gin.Context) {
+	Paginate(uc.BaseController, c, database.NewRepository[models.User](uc.Conn(c), usersListOptions))
 }
 
 // Show возвращает конкретного пользователя
@@ -44,7 +63,7 @@ func (uc *UsersController) Show(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := uc.DB.First(&user, userID).Error; err != nil {
+	if err := uc.Conn(c).First(&user, userID).Error; err != nil {
 		uc.NotFound(c, "User not found")
 		return
 	}
@@ -54,16 +73,17 @@ func (uc *UsersController) Show(c *gin.Context) {
 
 // Create создает нового пользователя
 func (uc *UsersController) Create(c *gin.Context) {
-	var user models.User
+	var params userParams
 
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&params); err != nil {
 		uc.ErrorResponse(c, 400, "Invalid request data")
 		return
 	}
+	user := params.user()
 
 	// Валидация
-	if errors := user.Validate(); len(errors) > 0 {
-		uc.ValidationError(c, errors)
+	if errs := models.Validate(&user, validation.Context{On: validation.OnCreate, DB: uc.Conn(c)}); errs.Any() {
+		uc.ValidationError(c, errs)
 		return
 	}
 
@@ -73,7 +93,7 @@ func (uc *UsersController) Create(c *gin.Context) {
 		return
 	}
 
-	if err := uc.DB.Create(&user).Error; err != nil {
+	if err := uc.Conn(c).Create(&user).Error; err != nil {
 		uc.ErrorResponse(c, 500, "Failed to create user")
 		return
 	}
@@ -91,12 +111,16 @@ func (uc *UsersController) Update(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := uc.DB.First(&user, userID).Error; err != nil {
+	if err := uc.Conn(c).First(&user, userID).Error; err != nil {
 		uc.NotFound(c, "User not found")
 		return
 	}
 
-	var updateData models.User
+	var updateData struct {
+		Name        string `json:"name"`
+		Email       string `json:"email"`
+		LockVersion *int   `json:"lock_version"`
+	}
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		uc.ErrorResponse(c, 400, "Invalid request data")
 		return
@@ -109,16 +133,25 @@ func (uc *UsersController) Update(c *gin.Context) {
 	if updateData.Email != "" {
 		user.Email = updateData.Email
 	}
+	// Версия, которую видел клиент: если запись с тех пор изменили, ответ 409
+	if updateData.LockVersion != nil {
+		user.LockVersion = *updateData.LockVersion
+	}
 
-	if err := uc.DB.Save(&user).Error; err != nil {
-		uc.ErrorResponse(c, 500, "Failed to update user")
+	if errs := models.Validate(&user, validation.Context{On: validation.OnUpdate, DB: uc.Conn(c)}); errs.Any() {
+		uc.ValidationError(c, errs)
+		return
+	}
+
+	if err := uc.Conn(c).Save(&user).Error; err != nil {
+		uc.SaveError(c, err, "Failed to update user")
 		return
 	}
 
 	uc.SuccessResponse(c, user)
 }
 
-// Destroy удаляет пользователя
+// Destroy помечает пользователя удаленным (см. models.SoftDeletable)
 func (uc *UsersController) Destroy(c *gin.Context) {
 	id := c.Param("id")
 	userID, err := strconv.Atoi(id)
@@ -128,15 +161,38 @@ func (uc *UsersController) Destroy(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := uc.DB.First(&user, userID).Error; err != nil {
+	if err := uc.Conn(c).First(&user, userID).Error; err != nil {
 		uc.NotFound(c, "User not found")
 		return
 	}
 
-	if err := uc.DB.Delete(&user).Error; err != nil {
+	if err := uc.Conn(c).Delete(&user).Error; err != nil {
 		uc.ErrorResponse(c, 500, "Failed to delete user")
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// Restore возвращает удаленного пользователя
+func (uc *UsersController) Restore(c *gin.Context) {
+	id := c.Param("id")
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		uc.ErrorResponse(c, 400, "Invalid user ID")
+		return
+	}
+
+	var user models.User
+	if err := uc.Conn(c).OnlyDeleted().First(&user, userID).Error; err != nil {
+		uc.NotFound(c, "Deleted user not found")
+		return
+	}
+
+	if err := uc.Conn(c).Restore(&user); err != nil {
+		uc.ErrorResponse(c, 500, "Failed to restore user")
+		return
+	}
+
+	uc.SuccessResponse(c, user)
+}
@@ -0,0 +1,71 @@
+package router
+
+import (
+	"sync"
+
+	"go-rails/framework/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DrawFunc добавляет маршруты приложения
+type DrawFunc func(r *gin.Engine, db *database.Database)
+
+var drawers struct {
+	sync.Mutex
+	list []DrawFunc
+}
+
+// Draw регистрирует маршруты приложения; вызывается в init() его пакета routes:
+//
+//	func init() {
+//		router.Draw(Draw)
+//	}
+func Draw(fn DrawFunc) {
+	drawers.Lock()
+	defer drawers.Unlock()
+	drawers.list = append(drawers.list, fn)
+}
+
+// Mount подключает маршруты, зарегистрированные через Draw. Если приложение
+// не зарегистрировало ни одного, подключаются встроенные маршруты SetupRoutes.
+func Mount(r *gin.Engine, db *database.Database) {
+	drawers.Lock()
+	list := append([]DrawFunc(nil), drawers.list...)
+	drawers.Unlock()
+
+	if len(list) == 0 {
+		SetupRoutes(r, db)
+		return
+	}
+	for _, fn := range list {
+		fn(r, db)
+	}
+}
+
+// ResourceController контроллер ресурса со стандартными действиями
+type ResourceController interface {
+	Index(c *gin.Context)
+	Show(c *gin.Context)
+	Create(c *gin.Context)
+	Update(c *gin.Context)
+	Destroy(c *gin.Context)
+}
+
+// Resources подключает REST-маршруты ресурса name:
+//
+//	GET    /posts          Index
+//	GET    /posts/:id      Show
+//	POST   /posts          Create
+//	PUT    /posts/:id      Update
+//	PATCH  /posts/:id      Update
+//	DELETE /posts/:id      Destroy
+func Resources(r gin.IRouter, name string, controller ResourceController) {
+	path := "/" + name
+	r.GET(path, controller.Index)
+	r.GET(path+"/:id", controller.Show)
+	r.POST(path, controller.Create)
+	r.PUT(path+"/:id", controller.Update)
+	r.PATCH(path+"/:id", controller.Update)
+	r.DELETE(path+"/:id", controller.Destroy)
+}
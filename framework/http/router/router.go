@@ -3,11 +3,14 @@ package router
 import (
 	"go-rails/framework/database"
 	"go-rails/framework/http/controllers"
+	"go-rails/framework/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRoutes настраивает все маршруты приложения
+// SetupRoutes настраивает встроенные маршруты фреймворка;
+// используются, пока приложение не зарегистрировало свои через Draw.
+// Без базы данных (db == nil) подключается только главная страница.
 func SetupRoutes(r *gin.Engine, db *database.Database) {
 	// Главная страница
 	r.GET("/", func(c *gin.Context) {
@@ -19,23 +22,32 @@ func SetupRoutes(r *gin.Engine, db *database.Database) {
 
 	// API маршруты
 	api := r.Group("/api/v1")
-	{
-		// Пользователи
-		usersController := controllers.NewUsersController(db)
-		api.GET("/users", usersController.Index)
-		api.GET("/users/:id", usersController.Show)
-		api.POST("/users", usersController.Create)
-		api.PUT("/users/:id", usersController.Update)
-		api.DELETE("/users/:id", usersController.Destroy)
+	UserRoutes(api, db)
+	AuthRoutes(api, db)
+}
 
-		// Аутентификация
-		authController := controllers.NewAuthController(db)
-		api.POST("/login", authController.Login)
-		api.POST("/register", authController.Register)
-		api.POST("/logout", authController.Logout)
+// UserRoutes подключает CRUD пользователей framework/models.User.
+// Без базы данных маршруты не подключаются: их обработчики не могут работать.
+func UserRoutes(api gin.IRouter, db *database.Database) {
+	if db == nil {
+		return
 	}
+	usersController := controllers.NewUsersController(db)
+	api.GET("/users", usersController.Index)
+	api.GET("/users/:id", usersController.Show)
+	api.POST("/users", usersController.Create)
+	api.PUT("/users/:id", usersController.Update)
+	api.DELETE("/users/:id", usersController.Destroy)
+	api.POST("/users/:id/restore", usersController.Restore)
+}
 
-	// Статические файлы
-	r.Static("/assets", "./public/assets")
-	r.StaticFile("/favicon.ico", "./public/favicon.ico")
+// AuthRoutes подключает вход, регистрацию и выход; без базы данных не подключает ничего
+func AuthRoutes(api gin.IRouter, db *database.Database) {
+	if db == nil {
+		return
+	}
+	authController := controllers.NewAuthController(db)
+	api.POST("/login", authController.Login)
+	api.POST("/register", middleware.Transactional(db), authController.Register)
+	api.POST("/logout", authController.Logout)
 }
@@ -0,0 +1,62 @@
+package inflector
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules собственные правила приложения из config/inflections.yml:
+//
+//	irregular:
+//	  octopus: octopi
+//	uncountable: [feedback]
+//	acronyms: [SKU]
+//	plural:
+//	  - ["(quiz)$", "${1}zes"]
+//	singular:
+//	  - ["(quiz)zes$", "${1}"]
+type Rules struct {
+	Irregular   map[string]string `yaml:"irregular"`
+	Uncountable []string          `yaml:"uncountable"`
+	Acronyms    []string          `yaml:"acronyms"`
+	Plural      [][2]string       `yaml:"plural"`
+	Singular    [][2]string       `yaml:"singular"`
+}
+
+// Apply добавляет правила к текущим
+func (r Rules) Apply() {
+	for singular, plural := range r.Irregular {
+		AddIrregular(singular, plural)
+	}
+	if len(r.Uncountable) > 0 {
+		AddUncountable(r.Uncountable...)
+	}
+	AddAcronym(r.Acronyms...)
+	for _, rule := range r.Plural {
+		AddPlural(rule[0], rule[1])
+	}
+	for _, rule := range r.Singular {
+		AddSingular(rule[0], rule[1])
+	}
+}
+
+// LoadFile читает правила из YAML-файла и применяет их.
+// Отсутствующий файл не считается ошибкой.
+func LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var rules Rules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("invalid %s: %v", path, err)
+	}
+	rules.Apply()
+	return nil
+}
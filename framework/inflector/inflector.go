@@ -0,0 +1,178 @@
+// Package inflector склоняет слова и преобразует имена моделей, таблиц и файлов:
+//
+//	inflector.Pluralize("person")       // people
+//	inflector.Singularize("categories") // category
+//	inflector.Camelize("blog_post")     // BlogPost
+//	inflector.Underscore("BlogPost")    // blog_post
+//	inflector.Humanize("author_id")     // Author
+//	inflector.Tableize("BlogPost")      // blog_posts
+//
+// Единственное и множественное число вычисляет github.com/jinzhu/inflection —
+// тот же пакет, которым GORM называет таблицы по умолчанию, поэтому собственные
+// правила (AddIrregular и др.) одинаково действуют на генераторы и на GORM.
+package inflector
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+)
+
+var acronyms = struct {
+	sync.RWMutex
+	words map[string]string // id -> ID
+}{
+	words: map[string]string{},
+}
+
+func init() {
+	AddAcronym("ID", "API", "URL", "URI", "HTTP", "JSON", "XML", "HTML", "SQL", "UUID", "IP")
+}
+
+// Pluralize возвращает множественное число: post -> posts, person -> people
+func Pluralize(word string) string {
+	return inflection.Plural(word)
+}
+
+// Singularize возвращает единственное число: posts -> post, people -> person
+func Singularize(word string) string {
+	return inflection.Singular(word)
+}
+
+// AddPlural добавляет правило множественного числа: регулярное выражение
+// и замена, например AddPlural("(quiz)$", "${1}zes")
+func AddPlural(find, replace string) {
+	inflection.AddPlural(find, replace)
+}
+
+// AddSingular добавляет правило единственного числа
+func AddSingular(find, replace string) {
+	inflection.AddSingular(find, replace)
+}
+
+// AddIrregular добавляет неправильную пару форм, например AddIrregular("octopus", "octopi")
+func AddIrregular(singular, plural string) {
+	inflection.AddIrregular(singular, plural)
+}
+
+// AddUncountable добавляет слова без множественного числа
+func AddUncountable(words ...string) {
+	inflection.AddUncountable(words...)
+}
+
+// AddAcronym добавляет аббревиатуры, которые Camelize и Humanize пишут
+// заглавными буквами: AddAcronym("SKU") дает sku_code -> SKUCode
+func AddAcronym(words ...string) {
+	acronyms.Lock()
+	defer acronyms.Unlock()
+	for _, word := range words {
+		acronyms.words[strings.ToLower(word)] = strings.ToUpper(word)
+	}
+}
+
+// Camelize переводит имя в CamelCase: blog_post -> BlogPost, author_id -> AuthorID
+func Camelize(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// LowerCamelize переводит имя в camelCase для переменных: blog_post -> blogPost
+func LowerCamelize(name string) string {
+	parts := words(name)
+	if len(parts) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, word := range parts[1:] {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// Underscore переводит имя в snake_case: BlogPost -> blog_post,
+// AuthorID -> author_id, HTTPServer -> http_server, blog-post -> blog_post
+func Underscore(name string) string {
+	return strings.Join(words(name), "_")
+}
+
+// Humanize переводит имя в текст для сообщений: author_id -> Author,
+// PasswordConfirmation -> Password confirmation
+func Humanize(name string) string {
+	parts := words(name)
+	if len(parts) > 1 && parts[len(parts)-1] == "id" {
+		parts = parts[:len(parts)-1]
+	}
+	for i, word := range parts {
+		if acronym, ok := acronym(word); ok {
+			parts[i] = acronym
+		} else if i == 0 {
+			parts[i] = capitalize(word)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Tableize возвращает имя таблицы модели: BlogPost -> blog_posts, person -> people
+func Tableize(name string) string {
+	return Pluralize(Underscore(name))
+}
+
+// Classify возвращает имя модели по имени таблицы: blog_posts -> BlogPost
+func Classify(name string) string {
+	return Camelize(Singularize(Underscore(name)))
+}
+
+// words разбивает имя в любой записи на слова в нижнем регистре
+func words(name string) []string {
+	var result []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			result = append(result, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Новое слово: aB, а в аббревиатуре — последняя заглавная перед строчной (HTTPServer)
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return result
+}
+
+// capitalize пишет слово с заглавной буквы, аббревиатуру — целиком заглавными
+func capitalize(word string) string {
+	if acronym, ok := acronym(word); ok {
+		return acronym
+	}
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// acronym возвращает аббревиатуру для слова в нижнем регистре
+func acronym(word string) (string, bool) {
+	acronyms.RLock()
+	defer acronyms.RUnlock()
+	acronym, ok := acronyms.words[word]
+	return acronym, ok
+}
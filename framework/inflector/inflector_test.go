@@ -0,0 +1,156 @@
+package inflector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// conversion входное имя и ожидаемый результат
+type conversion struct {
+	in, want string
+}
+
+func check(t *testing.T, name string, fn func(string) string, tests []conversion) {
+	t.Helper()
+	for _, tt := range tests {
+		if got := fn(tt.in); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCamelize(t *testing.T) {
+	check(t, "Camelize", Camelize, []conversion{
+		{"blog_post", "BlogPost"},
+		{"BlogPost", "BlogPost"},
+		{"blogPost", "BlogPost"},
+		{"blog-post", "BlogPost"},
+		{"author_id", "AuthorID"},
+		{"http_server", "HTTPServer"},
+		{"api_key", "APIKey"},
+		{"user_uuid", "UserUUID"},
+		{"post2_comment", "Post2Comment"},
+		{"", ""},
+	})
+	check(t, "LowerCamelize", LowerCamelize, []conversion{
+		{"blog_post", "blogPost"},
+		{"BlogPost", "blogPost"},
+		{"author_id", "authorID"},
+		{"id", "id"},
+		{"", ""},
+	})
+}
+
+func TestUnderscore(t *testing.T) {
+	check(t, "Underscore", Underscore, []conversion{
+		{"BlogPost", "blog_post"},
+		{"blogPost", "blog_post"},
+		{"blog_post", "blog_post"},
+		{"blog-post", "blog_post"},
+		{"blog post", "blog_post"},
+		{"AuthorID", "author_id"},
+		{"HTTPServer", "http_server"},
+		{"parseHTTPResponse", "parse_http_response"},
+		{"APIKey", "api_key"},
+		{"Post2Comment", "post2_comment"},
+		{"ID", "id"},
+	})
+}
+
+func TestTableize(t *testing.T) {
+	check(t, "Tableize", Tableize, []conversion{
+		{"BlogPost", "blog_posts"},
+		{"blog_post", "blog_posts"},
+		{"person", "people"},
+		{"Category", "categories"},
+		{"child", "children"},
+		{"Status", "statuses"},
+		{"address", "addresses"},
+	})
+}
+
+func TestClassify(t *testing.T) {
+	check(t, "Classify", Classify, []conversion{
+		{"blog_posts", "BlogPost"},
+		{"people", "Person"},
+		{"categories", "Category"},
+		{"statuses", "Status"},
+		{"BlogPosts", "BlogPost"},
+	})
+}
+
+func TestHumanize(t *testing.T) {
+	check(t, "Humanize", Humanize, []conversion{
+		{"author_id", "Author"},
+		{"AuthorID", "Author"},
+		{"id", "ID"},
+		{"PasswordConfirmation", "Password confirmation"},
+		{"first_name", "First name"},
+		{"api_key", "API key"},
+		{"user_url", "User URL"},
+	})
+}
+
+func TestPluralizeSingularize(t *testing.T) {
+	check(t, "Pluralize", Pluralize, []conversion{
+		{"post", "posts"},
+		{"person", "people"},
+		{"man", "men"},
+		{"sheep", "sheep"},
+	})
+	check(t, "Singularize", Singularize, []conversion{
+		{"posts", "post"},
+		{"people", "person"},
+		{"men", "man"},
+		{"sheep", "sheep"},
+	})
+}
+
+// TestLoadFile меняет глобальные правила, поэтому использует слова,
+// которых нет в остальных тестах
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := LoadFile(filepath.Join(dir, "missing.yml")); err != nil {
+		t.Errorf("LoadFile of a missing file: %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yml")
+	if err := os.WriteFile(invalid, []byte("irregular: [octopus"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(invalid); err == nil {
+		t.Error("LoadFile of invalid YAML: expected an error")
+	}
+
+	path := filepath.Join(dir, "inflections.yml")
+	rules := `irregular:
+  octopus: octopi
+uncountable: [feedback]
+acronyms: [SKU]
+plural:
+  - ["(quiz)$", "${1}zes"]
+singular:
+  - ["(quiz)zes$", "${1}"]
+`
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	check(t, "Tableize", Tableize, []conversion{
+		{"octopus", "octopi"},
+		{"feedback", "feedback"},
+		{"quiz", "quizzes"},
+	})
+	check(t, "Classify", Classify, []conversion{
+		{"octopi", "Octopus"},
+		{"feedback", "Feedback"},
+		{"quizzes", "Quiz"},
+	})
+	check(t, "Camelize", Camelize, []conversion{{"sku_code", "SKUCode"}})
+	check(t, "Underscore", Underscore, []conversion{{"SKUCode", "sku_code"}})
+	check(t, "Humanize", Humanize, []conversion{{"sku", "SKU"}})
+}
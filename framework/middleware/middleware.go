@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
+	"go-rails/framework/database"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -75,3 +81,135 @@ func RateLimit(limit int) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// PrimaryDatabaseKey ключ контекста, требующий работы с основной базой вместо реплик
+const PrimaryDatabaseKey = "gorails.database.primary"
+
+// lastWriteCookie хранит время последнего изменяющего запроса клиента
+const lastWriteCookie = "_gorails_last_write"
+
+// DatabaseSelector возвращает middleware, направляющее к основной базе
+// изменяющие запросы и чтения в течение delay после них,
+// чтобы клиент сразу видел свои изменения, даже если реплики отстают
+func DatabaseSelector(delay time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case "GET", "HEAD", "OPTIONS":
+			if value, err := c.Cookie(lastWriteCookie); err == nil {
+				if nanos, err := strconv.ParseInt(value, 10, 64); err == nil &&
+					time.Since(time.Unix(0, nanos)) < delay {
+					c.Set(PrimaryDatabaseKey, true)
+				}
+			}
+		default:
+			c.Set(PrimaryDatabaseKey, true)
+			maxAge := int(delay/time.Second) + 1
+			c.SetCookie(lastWriteCookie, strconv.FormatInt(time.Now().UnixNano(), 10), maxAge, "/", "", false, true)
+		}
+
+		c.Next()
+	}
+}
+
+// TransactionKey ключ контекста, под которым хранится транзакция запроса
+const TransactionKey = "gorails.database.tx"
+
+// errRollbackRequest откатывает транзакцию запроса, завершившегося ошибкой
+var errRollbackRequest = errors.New("request failed")
+
+// Transactional возвращает middleware, выполняющее действие контроллера в одной
+// транзакции. Транзакция фиксируется, если ответ имеет статус ниже 400,
+// и откатывается при ошибочном статусе или панике. Контроллеры получают её
+// через BaseController.Conn.
+//
+// Ответ буферизуется и отправляется клиенту только после фиксации: если COMMIT
+// не удался, вместо ответа контроллера уходит 500. Поэтому потоковые ответы
+// (c.Stream, SSE) под Transactional не поддерживаются.
+func Transactional(db *database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := c.Writer
+		buffer := &bufferedWriter{ResponseWriter: writer, status: http.StatusOK}
+		c.Writer = buffer
+		// При панике ответ пишет Recovery, уже в исходный writer
+		defer func() { c.Writer = writer }()
+
+		err := db.Transaction(func(tx *database.Database) error {
+			c.Set(TransactionKey, tx)
+			c.Set(PrimaryDatabaseKey, true)
+			c.Next()
+
+			if c.Writer.Status() >= 400 || len(c.Errors) > 0 {
+				return errRollbackRequest
+			}
+			return nil
+		})
+
+		c.Writer = writer
+		if err != nil && err != errRollbackRequest {
+			c.Error(err)
+			c.AbortWithStatusJSON(500, gin.H{"success": false, "error": "Transaction failed"})
+			return
+		}
+		if err := buffer.flush(); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// bufferedWriter задерживает статус и тело ответа до фиксации транзакции
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+// WriteHeader запоминает статус; он отправляется в flush
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush ничего не делает: до фиксации транзакции клиент не должен получить ответ
+func (w *bufferedWriter) Flush() {}
+
+// flush отправляет накопленный ответ в исходный writer
+func (w *bufferedWriter) flush() error {
+	w.ResponseWriter.WriteHeader(w.status)
+	if !w.written {
+		return nil
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	return err
+}
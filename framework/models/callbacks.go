@@ -0,0 +1,193 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go-rails/framework/database"
+	"go-rails/framework/validation"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Event момент жизненного цикла модели
+type Event string
+
+// События в порядке выполнения. Для создания:
+// before_validate, after_validate (models.Validate), before_save, before_create,
+// after_create, after_save, after_commit или after_rollback.
+const (
+	BeforeValidate Event = "before_validate"
+	AfterValidate  Event = "after_validate"
+	BeforeSave     Event = "before_save"
+	AfterSave      Event = "after_save"
+	BeforeCreate   Event = "before_create"
+	AfterCreate    Event = "after_create"
+	BeforeUpdate   Event = "before_update"
+	AfterUpdate    Event = "after_update"
+	BeforeDestroy  Event = "before_destroy"
+	AfterDestroy   Event = "after_destroy"
+	AfterCommit    Event = "after_commit"
+	AfterRollback  Event = "after_rollback"
+)
+
+// CallbackFunc обработчик события. db выполняет запросы в той же транзакции,
+// что и операция; в after_commit и after_rollback транзакция уже завершена и db равна nil.
+// Ошибка любого обработчика, кроме after_commit и after_rollback,
+// останавливает цепочку и откатывает операцию.
+type CallbackFunc func(db *database.Database, record interface{}) error
+
+// Validatable модель с проверками, см. Validate
+type Validatable interface {
+	Validate(ctx validation.Context) validation.Errors
+}
+
+var callbacks = struct {
+	sync.RWMutex
+	byModel map[reflect.Type]map[Event][]CallbackFunc
+	global  map[Event][]CallbackFunc
+}{
+	byModel: make(map[reflect.Type]map[Event][]CallbackFunc),
+	global:  make(map[Event][]CallbackFunc),
+}
+
+// On регистрирует обработчик события для модели, например в init() её файла:
+//
+//	models.On(&User{}, models.BeforeSave, func(db *database.Database, record interface{}) error {
+//		user := record.(*User)
+//		user.Email = strings.ToLower(user.Email)
+//		return nil
+//	})
+func On(model interface{}, event Event, fn CallbackFunc) {
+	typ := modelType(reflect.TypeOf(model))
+
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	if callbacks.byModel[typ] == nil {
+		callbacks.byModel[typ] = make(map[Event][]CallbackFunc)
+	}
+	callbacks.byModel[typ][event] = append(callbacks.byModel[typ][event], fn)
+}
+
+// OnAny регистрирует обработчик события для всех моделей,
+// например для аудита или сброса кеша. Выполняется после обработчиков модели.
+func OnAny(event Event, fn CallbackFunc) {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	callbacks.global[event] = append(callbacks.global[event], fn)
+}
+
+// Validate выполняет before_validate, проверки модели и after_validate.
+// Ошибка обработчика возвращается как ошибка поля "base".
+func Validate(record Validatable, ctx validation.Context) validation.Errors {
+	if err := run(ctx.DB, record, BeforeValidate); err != nil {
+		return validation.Errors{"base": {err.Error()}}
+	}
+	errs := record.Validate(ctx)
+	if err := run(ctx.DB, record, AfterValidate); err != nil {
+		errs.Add("base", err.Error())
+	}
+	return errs
+}
+
+// run выполняет обработчики события до первой ошибки
+func run(db *database.Database, record interface{}, event Event) error {
+	callbacks.RLock()
+	chain := append([]CallbackFunc(nil), callbacks.byModel[modelType(reflect.TypeOf(record))][event]...)
+	chain = append(chain, callbacks.global[event]...)
+	callbacks.RUnlock()
+
+	for _, fn := range chain {
+		if err := fn(db, record); err != nil {
+			return fmt.Errorf("%s halted: %v", event, err)
+		}
+	}
+	return nil
+}
+
+// modelType возвращает тип структуры модели без указателей
+func modelType(typ reflect.Type) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// Обработчики подключаются к GORM, поэтому срабатывают для Create, Save,
+// Update и Delete через database.Database и напрямую через GORM
+func init() {
+	gorm.DefaultCallback.Create().Before("gorm:before_create").
+		Register("gorails:before_create", dispatch(BeforeSave, BeforeCreate))
+	gorm.DefaultCallback.Create().After("gorm:after_create").
+		Register("gorails:after_create", dispatch(AfterCreate, AfterSave))
+	gorm.DefaultCallback.Create().After("gorm:commit_or_rollback_transaction").
+		Register("gorails:after_commit", dispatchCompletion)
+
+	gorm.DefaultCallback.Update().Before("gorm:before_update").
+		Register("gorails:before_update", dispatch(BeforeSave, BeforeUpdate))
+	gorm.DefaultCallback.Update().After("gorm:after_update").
+		Register("gorails:after_update", dispatch(AfterUpdate, AfterSave))
+	gorm.DefaultCallback.Update().After("gorm:commit_or_rollback_transaction").
+		Register("gorails:after_commit", dispatchCompletion)
+
+	gorm.DefaultCallback.Delete().Before("gorm:before_delete").
+		Register("gorails:before_destroy", dispatch(BeforeDestroy))
+	gorm.DefaultCallback.Delete().After("gorm:after_delete").
+		Register("gorails:after_destroy", dispatch(AfterDestroy))
+	gorm.DefaultCallback.Delete().After("gorm:commit_or_rollback_transaction").
+		Register("gorails:after_commit", dispatchCompletion)
+}
+
+// dispatch возвращает обработчик GORM, выполняющий события по порядку
+func dispatch(events ...Event) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		if scope.HasError() {
+			return
+		}
+		db := database.FromScope(scope)
+		for _, record := range records(scope) {
+			for _, event := range events {
+				if err := run(db, record, event); err != nil {
+					scope.Err(err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// dispatchCompletion выполняет after_commit или after_rollback.
+// Внутри database.Database.Transaction они откладываются до её завершения.
+func dispatchCompletion(scope *gorm.Scope) {
+	db := database.FromScope(scope)
+	for _, record := range records(scope) {
+		record := record
+		if scope.HasError() {
+			run(nil, record, AfterRollback)
+			continue
+		}
+		db.AfterCommit(func() { run(nil, record, AfterCommit) })
+		db.AfterRollback(func() { run(nil, record, AfterRollback) })
+	}
+}
+
+// records возвращает записи операции: саму запись или элементы среза
+func records(scope *gorm.Scope) []interface{} {
+	value := scope.IndirectValue()
+	switch value.Kind() {
+	case reflect.Struct:
+		return []interface{}{scope.Value}
+	case reflect.Slice:
+		result := make([]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			if item.Kind() != reflect.Ptr && item.CanAddr() {
+				item = item.Addr()
+			}
+			result = append(result, item.Interface())
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package models
+
+// Lockable встраивается в модель для оптимистической блокировки:
+// database.Database.Save обновляет запись, только если lock_version
+// в базе совпадает с версией в модели, и увеличивает её на единицу.
+// Иначе Save возвращает *database.StaleObjectError.
+// Таблице нужна колонка lock_version (integer NOT NULL DEFAULT 0).
+type Lockable struct {
+	LockVersion int `json:"lock_version" gorm:"not null;default:0"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SoftDeletable встраивается в модель, чтобы Delete помечал запись удаленной
+// вместо удаления строки. Такие записи не попадают в выборки database.Database;
+// чтобы их увидеть, используйте db.WithDeleted() или db.OnlyDeleted(),
+// чтобы вернуть — db.Restore, чтобы удалить окончательно — db.Purge.
+// Таблице нужна колонка deleted_at (NULL для действующих записей).
+type SoftDeletable struct {
+	DeletedAt *time.Time `json:"deleted_at,omitempty" sql:"index"`
+}
+
+// IsDeleted сообщает, помечена ли запись удаленной
+func (s SoftDeletable) IsDeleted() bool {
+	return s.DeletedAt != nil
+}
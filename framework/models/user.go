@@ -5,7 +5,8 @@ import (
 	"regexp"
 	"time"
 
-	"github.com/jinzhu/gorm"
+	"go-rails/framework/validation"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,6 +18,11 @@ type User struct {
 	Password  string    `json:"-" gorm:"not null"` // "-" скрывает поле из JSON
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	SoftDeletable
+	Lockable
+
+	// PasswordConfirmation повтор пароля для validation.Confirmation, не хранится в базе
+	PasswordConfirmation string `json:"-" gorm:"-"`
 }
 
 // TableName возвращает имя таблицы
@@ -24,50 +30,24 @@ func (User) TableName() string {
 	return "users"
 }
 
-// BeforeCreate выполняется перед созданием записи
-func (u *User) BeforeCreate(scope *gorm.Scope) error {
-	u.CreatedAt = time.Now()
-	u.UpdatedAt = time.Now()
-	return nil
-}
-
-// BeforeUpdate выполняется перед обновлением записи
-func (u *User) BeforeUpdate(scope *gorm.Scope) error {
-	u.UpdatedAt = time.Now()
-	return nil
-}
-
-// Validate выполняет валидацию модели
-func (u *User) Validate() map[string]string {
-	errors := make(map[string]string)
-
-	// Валидация имени
-	if u.Name == "" {
-		errors["name"] = "Name is required"
-	} else if len(u.Name) < 2 {
-		errors["name"] = "Name must be at least 2 characters long"
-	} else if len(u.Name) > 50 {
-		errors["name"] = "Name must be less than 50 characters"
-	}
-
-	// Валидация email
-	if u.Email == "" {
-		errors["email"] = "Email is required"
-	} else {
-		emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-		if !emailRegex.MatchString(u.Email) {
-			errors["email"] = "Invalid email format"
-		}
-	}
+// userValidator правила проверки пользователя
+var userValidator = validation.New(
+	validation.Presence("Name"),
+	validation.Length("Name", 2, 50),
+	validation.Presence("Email"),
+	validation.Format("Email", emailFormat),
+	validation.Uniqueness("Email"),
+	validation.Presence("Password").On(validation.OnCreate),
+	validation.Length("Password", 6, 0),
+	validation.Confirmation("Password"),
+)
 
-	// Валидация пароля
-	if u.Password == "" {
-		errors["password"] = "Password is required"
-	} else if len(u.Password) < 6 {
-		errors["password"] = "Password must be at least 6 characters long"
-	}
+// emailFormat допустимый формат email
+var emailFormat = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
-	return errors
+// Validate выполняет валидацию модели в контексте создания или обновления
+func (u *User) Validate(ctx validation.Context) validation.Errors {
+	return userValidator.Validate(u, ctx)
 }
 
 // HashPassword хеширует пароль
@@ -0,0 +1,257 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// check данные, доступные правилу при проверке
+type check struct {
+	record interface{}
+	value  reflect.Value // запись
+	field  reflect.Value // проверяемое поле
+	ctx    Context
+}
+
+// Rule правило проверки одного поля
+type Rule struct {
+	field     string
+	check     func(c check) []string
+	on        string
+	condition func(record interface{}) bool
+	message   string
+	verbatim  bool // сообщения выводятся без названия поля
+}
+
+// On ограничивает правило контекстом OnCreate или OnUpdate
+func (r Rule) On(context string) Rule {
+	r.on = context
+	return r
+}
+
+// If выполняет правило, только если condition возвращает true
+func (r Rule) If(condition func(record interface{}) bool) Rule {
+	r.condition = condition
+	return r
+}
+
+// Message заменяет сообщение правила
+func (r Rule) Message(message string) Rule {
+	r.message = message
+	return r
+}
+
+// Presence требует непустое значение: не нулевое, а для строк — не из одних пробелов
+func Presence(field string) Rule {
+	return Rule{field: field, check: func(c check) []string {
+		if isBlank(c.field) {
+			return []string{"is required"}
+		}
+		return nil
+	}}
+}
+
+// Length ограничивает длину строки в символах; 0 означает отсутствие границы.
+// Пустые значения не проверяются — для них есть Presence.
+func Length(field string, min, max int) Rule {
+	return Rule{field: field, check: func(c check) []string {
+		if isBlank(c.field) {
+			return nil
+		}
+		length := utf8.RuneCountInString(fmt.Sprint(c.field.Interface()))
+		if min > 0 && length < min {
+			return []string{fmt.Sprintf("must be at least %d characters long", min)}
+		}
+		if max > 0 && length > max {
+			return []string{fmt.Sprintf("must be at most %d characters long", max)}
+		}
+		return nil
+	}}
+}
+
+// Format требует соответствия регулярному выражению
+func Format(field string, pattern *regexp.Regexp) Rule {
+	return Rule{field: field, check: func(c check) []string {
+		if isBlank(c.field) {
+			return nil
+		}
+		if !pattern.MatchString(fmt.Sprint(c.field.Interface())) {
+			return []string{"has invalid format"}
+		}
+		return nil
+	}}
+}
+
+// Inclusion требует, чтобы значение было одним из values
+func Inclusion(field string, values ...interface{}) Rule {
+	allowed := make([]string, len(values))
+	for i, value := range values {
+		allowed[i] = fmt.Sprint(value)
+	}
+	return Rule{field: field, check: func(c check) []string {
+		if isBlank(c.field) {
+			return nil
+		}
+		value := fmt.Sprint(c.field.Interface())
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return []string{"must be one of: " + strings.Join(allowed, ", ")}
+	}}
+}
+
+// NumberOption ограничение для Numericality
+type NumberOption func(n *numberOptions)
+
+type numberOptions struct {
+	min, max    *float64
+	onlyInteger bool
+}
+
+// Min ограничивает значение снизу (включительно)
+func Min(min float64) NumberOption {
+	return func(n *numberOptions) { n.min = &min }
+}
+
+// Max ограничивает значение сверху (включительно)
+func Max(max float64) NumberOption {
+	return func(n *numberOptions) { n.max = &max }
+}
+
+// OnlyInteger допускает только целые числа
+func OnlyInteger() NumberOption {
+	return func(n *numberOptions) { n.onlyInteger = true }
+}
+
+// Numericality требует число (числовое поле или строка с числом) в заданных границах
+func Numericality(field string, options ...NumberOption) Rule {
+	var opts numberOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return Rule{field: field, check: func(c check) []string {
+		field := reflect.Indirect(c.field)
+		if !field.IsValid() {
+			return nil
+		}
+
+		var number float64
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = float64(field.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = float64(field.Uint())
+		case reflect.Float32, reflect.Float64:
+			number = field.Float()
+		case reflect.String:
+			if strings.TrimSpace(field.String()) == "" {
+				return nil
+			}
+			var err error
+			if number, err = strconv.ParseFloat(strings.TrimSpace(field.String()), 64); err != nil {
+				return []string{"is not a number"}
+			}
+		default:
+			return []string{"is not a number"}
+		}
+
+		var messages []string
+		if opts.onlyInteger && number != math.Trunc(number) {
+			messages = append(messages, "must be an integer")
+		}
+		if opts.min != nil && number < *opts.min {
+			messages = append(messages, "must be greater than or equal to "+formatNumber(*opts.min))
+		}
+		if opts.max != nil && number > *opts.max {
+			messages = append(messages, "must be less than or equal to "+formatNumber(*opts.max))
+		}
+		return messages
+	}}
+}
+
+// Uniqueness требует, чтобы в таблице модели не было другой записи с тем же значением.
+// scope — поля, в пределах значений которых проверяется уникальность.
+// Проверка выполняется через Context.DB и пропускается, если база не передана;
+// окончательно уникальность гарантирует только индекс (см. database.IsUniqueViolation).
+func Uniqueness(field string, scope ...string) Rule {
+	return Rule{field: field, check: func(c check) []string {
+		if c.ctx.DB == nil || isBlank(c.field) {
+			return nil
+		}
+
+		model := c.ctx.DB.NewScope(c.record)
+		column, ok := model.FieldByName(field)
+		if !ok {
+			return nil
+		}
+
+		query := c.ctx.DB.DB.Table(model.TableName()).
+			Where(model.Quote(column.DBName)+" = ?", c.field.Interface())
+		for _, name := range scope {
+			if f, ok := model.FieldByName(name); ok {
+				query = query.Where(model.Quote(f.DBName)+" = ?", f.Field.Interface())
+			}
+		}
+		if !model.PrimaryKeyZero() {
+			query = query.Where(model.Quote(model.PrimaryKey())+" <> ?", model.PrimaryKeyValue())
+		}
+
+		var count int
+		if err := query.Count(&count).Error; err != nil || count == 0 {
+			return nil
+		}
+		return []string{"has already been taken"}
+	}}
+}
+
+// Confirmation требует, чтобы поле <field>Confirmation совпадало с полем.
+// Если подтверждение не заполнено, правило не проверяется.
+func Confirmation(field string) Rule {
+	return Rule{field: field, check: func(c check) []string {
+		confirmation := c.value.FieldByName(field + "Confirmation")
+		if !confirmation.IsValid() {
+			panic("validation: no field " + field + "Confirmation")
+		}
+		if isBlank(confirmation) {
+			return nil
+		}
+		if !reflect.DeepEqual(confirmation.Interface(), c.field.Interface()) {
+			return []string{"doesn't match confirmation"}
+		}
+		return nil
+	}}
+}
+
+// Custom проверяет запись функцией; ошибка становится сообщением поля как есть
+func Custom(field string, fn func(record interface{}) error) Rule {
+	return Rule{field: field, verbatim: true, check: func(c check) []string {
+		if err := fn(c.record); err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}}
+}
+
+// isBlank сообщает, пусто ли значение
+func isBlank(value reflect.Value) bool {
+	value = reflect.Indirect(value)
+	if !value.IsValid() {
+		return true
+	}
+	if value.Kind() == reflect.String {
+		return strings.TrimSpace(value.String()) == ""
+	}
+	return value.IsZero()
+}
+
+// formatNumber выводит число без лишних нулей
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,119 @@
+// Package validation описывает проверки моделей набором декларативных правил:
+//
+//	var userValidator = validation.New(
+//		validation.Presence("Name"),
+//		validation.Length("Name", 2, 50),
+//		validation.Format("Email", emailFormat),
+//		validation.Uniqueness("Email"),
+//		validation.Presence("Password").On(validation.OnCreate),
+//		validation.Confirmation("Password"),
+//	)
+//
+//	errs := userValidator.Validate(&user, validation.Context{On: validation.OnCreate, DB: db})
+//
+// Поля указываются по имени в Go, ошибки возвращаются по имени из тега json.
+package validation
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"go-rails/framework/database"
+	"go-rails/framework/inflector"
+)
+
+// Контексты, в которых выполняется проверка
+const (
+	OnCreate = "create"
+	OnUpdate = "update"
+)
+
+// Context задает условия проверки
+type Context struct {
+	On string             // OnCreate, OnUpdate или пусто — правила с On пропускаются
+	DB *database.Database // нужна для Uniqueness; без неё правило пропускается
+}
+
+// Errors сообщения об ошибках по полям; у поля может быть несколько ошибок
+type Errors map[string][]string
+
+// Add добавляет сообщение для поля
+func (e Errors) Add(field, message string) {
+	e[field] = append(e[field], message)
+}
+
+// Any сообщает, есть ли ошибки
+func (e Errors) Any() bool {
+	return len(e) > 0
+}
+
+// On возвращает ошибки поля
+func (e Errors) On(field string) []string {
+	return e[field]
+}
+
+// Error объединяет все сообщения, чтобы Errors можно было вернуть как error
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var messages []string
+	for _, field := range fields {
+		messages = append(messages, e[field]...)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Validator набор правил для модели
+type Validator struct {
+	rules []Rule
+}
+
+// New создает набор правил. Правила выполняются в порядке объявления
+func New(rules ...Rule) *Validator {
+	return &Validator{rules: rules}
+}
+
+// Validate проверяет запись (структуру или указатель на неё) и возвращает ошибки
+func (v *Validator) Validate(record interface{}, ctx Context) Errors {
+	errs := make(Errors)
+	value := reflect.Indirect(reflect.ValueOf(record))
+
+	for _, rule := range v.rules {
+		if rule.on != "" && rule.on != ctx.On {
+			continue
+		}
+		if rule.condition != nil && !rule.condition(record) {
+			continue
+		}
+
+		field, ok := value.Type().FieldByName(rule.field)
+		if !ok {
+			panic("validation: " + value.Type().Name() + " has no field " + rule.field)
+		}
+		fieldValue := value.FieldByIndex(field.Index)
+
+		for _, message := range rule.check(check{record: record, value: value, field: fieldValue, ctx: ctx}) {
+			switch {
+			case rule.message != "":
+				message = rule.message
+			case !rule.verbatim:
+				message = inflector.Humanize(rule.field) + " " + message
+			}
+			errs.Add(jsonName(field), message)
+		}
+	}
+	return errs
+}
+
+// jsonName возвращает имя поля из тега json или snake_case имени
+func jsonName(field reflect.StructField) string {
+	if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+		return tag
+	}
+	return inflector.Underscore(field.Name)
+}